@@ -0,0 +1,61 @@
+package logman
+
+import "sync/atomic"
+
+// LevelSetter is implemented by loggers which level can be changed
+// at runtime.
+type LevelSetter interface {
+	SetLevel(level Level)
+}
+
+// OwnLevelGetter is implemented by loggers which level may follow
+// the Logman level. OwnLevel returns NotSet while it does.
+type OwnLevelGetter interface {
+	OwnLevel() Level
+}
+
+// AtomicLevel is a Level which is safe to read and change concurrently.
+// A level created by Logman.NewChannelLevel follows the Logman level
+// while it's NotSet.
+type AtomicLevel struct {
+	level  atomic.Uint32
+	parent *AtomicLevel
+}
+
+func NewAtomicLevel(level Level) *AtomicLevel {
+	a := &AtomicLevel{}
+	a.SetLevel(level)
+
+	return a
+}
+
+func (a *AtomicLevel) Level() Level {
+	level := Level(a.level.Load())
+	if level == NotSet && a.parent != nil {
+		return a.parent.Level()
+	}
+
+	return level
+}
+func (a *AtomicLevel) SetLevel(level Level) {
+	a.level.Store(uint32(level))
+}
+
+// OwnLevel returns the level set, which is NotSet if the level follows
+// the Logman one.
+func (a *AtomicLevel) OwnLevel() Level {
+	return Level(a.level.Load())
+}
+
+// NewChannelLevel returns the level for a channel configured with
+// the given one. The channel follows the Logman level, including
+// the changes made by SetLevel, while its own level is NotSet.
+// The level of a nil Logman follows nothing.
+func (lm *Logman) NewChannelLevel(level Level) *AtomicLevel {
+	a := NewAtomicLevel(level)
+	if lm != nil {
+		a.parent = lm.level
+	}
+
+	return a
+}
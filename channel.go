@@ -0,0 +1,114 @@
+package logman
+
+import (
+	"context"
+	"fmt"
+)
+
+// channelLogger is a named channel of a Logman. It respects the Logman
+// fields, so it behaves like the Logman itself with the channel used
+// instead of the default one.
+type channelLogger struct {
+	lm   *Logman
+	name string
+}
+
+func (l channelLogger) Debug(msg string, fields ...Fields) {
+	l.Log(DebugLevel, msg, fields...)
+}
+func (l channelLogger) Info(msg string, fields ...Fields) {
+	l.Log(InfoLevel, msg, fields...)
+}
+func (l channelLogger) Warning(msg string, fields ...Fields) {
+	l.Log(WarningLevel, msg, fields...)
+}
+func (l channelLogger) Error(msg string, fields ...Fields) {
+	l.Log(ErrorLevel, msg, fields...)
+}
+func (l channelLogger) Critical(msg string, fields ...Fields) {
+	l.Log(CriticalLevel, msg, fields...)
+}
+func (l channelLogger) Log(level Level, msg string, fields ...Fields) {
+	l.LogContext(context.Background(), level, msg, fields...)
+}
+func (l channelLogger) DebugContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	l.LogContext(ctx, DebugLevel, msg, fields...)
+}
+func (l channelLogger) InfoContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	l.LogContext(ctx, InfoLevel, msg, fields...)
+}
+func (l channelLogger) WarningContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	l.LogContext(ctx, WarningLevel, msg, fields...)
+}
+func (l channelLogger) ErrorContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	l.LogContext(ctx, ErrorLevel, msg, fields...)
+}
+func (l channelLogger) CriticalContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	l.LogContext(ctx, CriticalLevel, msg, fields...)
+}
+func (l channelLogger) LogContext(
+	ctx context.Context,
+	level Level,
+	msg string,
+	fields ...Fields,
+) {
+	l.lm.logTo(ctx, l.name, level, msg, fields...)
+}
+func (l channelLogger) Level() Level {
+	ch, exists := l.lm.Channels(l.name)[l.name]
+	if !exists {
+		return NotSet
+	}
+
+	return ch.Level()
+}
+func (l channelLogger) With(fields Fields) Logger {
+	return channelLogger{
+		lm:   l.lm.with(fields),
+		name: l.name,
+	}
+}
+
+// Channel returns the named channel.
+// The channel is looked up on every call, so it survives reloads
+// as long as the new config has the channel.
+// A no-op logger is returned for unknown channels,
+// use LookupChannel to detect them.
+func (lm *Logman) Channel(name string) Logger {
+	ch, err := lm.LookupChannel(name)
+	if err != nil {
+		return NewNop()
+	}
+
+	return ch
+}
+
+// LookupChannel returns the named channel or UnknownChannelErr if there
+// is no such channel.
+func (lm *Logman) LookupChannel(name string) (Logger, error) {
+	if _, exists := lm.state.Load().channels[name]; !exists {
+		return nil, fmt.Errorf("Channel \"%s\": %w", name, UnknownChannelErr)
+	}
+
+	return channelLogger{lm: lm, name: name}, nil
+}
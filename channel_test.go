@@ -0,0 +1,60 @@
+package logman_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Chekunin/logman"
+)
+
+func TestChannelLevel(t *testing.T) {
+	std, other := &bytes.Buffer{}, &bytes.Buffer{}
+
+	lm, err := logman.New(logman.Config{
+		DefaultChannel: "std",
+		Level:          logman.InfoLevel,
+		Channels: logman.ChannelConfigs{
+			"std":   logman.StdLoggerConfig{Output: std},
+			"other": logman.StdLoggerConfig{Output: other},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := lm.SetChannelLevel("std", logman.DebugLevel); err != nil {
+		t.Fatal(err)
+	}
+
+	lm.Debug("default")
+	lm.Channel("std").Debug("channel")
+	lm.Channel("other").Debug("other")
+
+	if lm.Level() != logman.InfoLevel {
+		t.Errorf("Logman level %s, expected info", lm.Level())
+	}
+	if level := lm.Channel("std").Level(); level != logman.DebugLevel {
+		t.Errorf("Channel level %s, expected debug", level)
+	}
+	for _, msg := range []string{"default", "channel"} {
+		if !strings.Contains(std.String(), "[DEBUG] "+msg) {
+			t.Errorf("Debug entry %q expected in %q", msg, std.String())
+		}
+	}
+	if other.Len() != 0 {
+		t.Errorf("No entries expected in the channel following the Logman "+
+			"level, got %q", other.String())
+	}
+
+	if err := lm.SetChannelLevel("std", logman.NotSet); err != nil {
+		t.Fatal(err)
+	}
+	std.Reset()
+	lm.Debug("default")
+
+	if std.Len() != 0 {
+		t.Errorf("No entries expected once the channel level is unset, "+
+			"got %q", std.String())
+	}
+}
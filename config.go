@@ -1,6 +1,10 @@
 package logman
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
 
 type ChannelConfig interface {
 	DriverName() string
@@ -23,6 +27,16 @@ type Config struct {
 	DefaultChannel string
 	Level          Level
 	Channels       ChannelConfigs
+	// EnableCaller captures the caller of the logging functions
+	// and passes it to the drivers in a Record.
+	EnableCaller bool
+	// StackTraceLevel captures the stack trace of entries of the level
+	// or more severe ones, disabled if not set.
+	StackTraceLevel Level
+	// CallerSkip is the number of frames to skip after the frames
+	// of logman when capturing the caller, e.g. 1 for a package wrapping
+	// the logging functions. See also Logman.WithCallerSkip.
+	CallerSkip int
 }
 
 func NewConfig() Config {
@@ -58,6 +72,34 @@ func (cfg Config) WithChannels(chs ChannelArbitraryConfigs) Config {
 
 	return cfg
 }
+
+// withRecord returns ctx carrying the record of the entry if capturing
+// is enabled and the caller didn't provide one.
+func (cfg Config) withRecord(
+	ctx context.Context,
+	level Level,
+	skip int,
+) context.Context {
+	withStack := cfg.StackTraceLevel != NotSet && level <= cfg.StackTraceLevel
+	if !cfg.EnableCaller && !withStack {
+		return ctx
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if _, exists := RecordFromContext(ctx); exists {
+		return ctx
+	}
+
+	rec := CaptureRecord(cfg.CallerSkip+skip, withStack)
+	if !cfg.EnableCaller {
+		rec.Caller = runtime.Frame{}
+	}
+
+	return ContextWithRecord(ctx, rec)
+}
 func (c *Config) setDefaults() *Config {
 	if c.Level == NotSet {
 		c.Level = InfoLevel
@@ -70,6 +112,20 @@ func (cfg Config) validate() error {
 		return fmt.Errorf("Level \"%d\": %w", cfg.Level, InvalidConfigValueErr)
 	}
 
+	if cfg.StackTraceLevel > DebugLevel {
+		return fmt.Errorf(
+			"Stack trace level \"%d\": %w",
+			cfg.StackTraceLevel,
+			InvalidConfigValueErr,
+		)
+	}
+
+	if cfg.CallerSkip < 0 {
+		return fmt.Errorf(
+			"Caller skip \"%d\": %w", cfg.CallerSkip, InvalidConfigValueErr,
+		)
+	}
+
 	if len(cfg.Channels) == 0 {
 		return NoChannelsConfiguredErr
 	}
@@ -102,5 +158,10 @@ func (cfg Config) validate() error {
 		}
 	}
 
+	// composite channels may use each other as long as there is no cycle
+	if _, err := creationOrder(cfg.Channels); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,223 @@
+package logman
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ApplyEnv overrides the config with environment variables named
+// with the given prefix:
+//
+//	<PREFIX>_DEFAULT_CHANNEL=stack
+//	<PREFIX>_LEVEL=debug
+//	<PREFIX>_ENABLE_CALLER=true
+//	<PREFIX>_STACK_TRACE_LEVEL=error
+//	<PREFIX>_CALLER_SKIP=1
+//	<PREFIX>_CHANNELS_<CHANNEL>_LEVEL=warning
+//	<PREFIX>_CHANNELS_<CHANNEL>_<OPTION>=value
+//
+// Channel names are matched case-insensitively with every character other
+// than a letter or a digit replaced by "_". Options go to
+// ChannelArbitraryConfig.Extra, ENABLE_CALLER becomes "enableCaller"
+// unless Extra already has an option matching it case-insensitively.
+// Values are converted to the type of the existing option, otherwise
+// "true"/"false" become bool, integers become int and values with commas
+// become lists. Only channels configured with ChannelArbitraryConfig
+// can be overridden. Unknown variables with the prefix are errors
+// the same as unknown channels.
+func (cfg Config) ApplyEnv(prefix string) (Config, error) {
+	prefix = strings.ToUpper(strings.TrimSuffix(prefix, "_")) + "_"
+
+	chs := make(ChannelConfigs, len(cfg.Channels))
+	for name, chCfg := range cfg.Channels {
+		chs[name] = chCfg
+	}
+	cfg.Channels = chs
+
+	env := os.Environ()
+	sort.Strings(env)
+
+	for _, kv := range env {
+		key, val, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		if err := cfg.applyEnvVar(
+			strings.TrimPrefix(key, prefix),
+			val,
+		); err != nil {
+			return cfg, fmt.Errorf("Env \"%s\": %w", key, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) applyEnvVar(key string, val string) error {
+	switch key {
+	case "DEFAULT_CHANNEL":
+		cfg.DefaultChannel = val
+		return nil
+	case "LEVEL":
+		level, err := ParseLevel(val)
+		if err != nil {
+			return err
+		}
+		cfg.Level = level
+		return nil
+	case "ENABLE_CALLER":
+		enableCaller, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		cfg.EnableCaller = enableCaller
+		return nil
+	case "CALLER_SKIP":
+		skip, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		cfg.CallerSkip = skip
+		return nil
+	case "STACK_TRACE_LEVEL":
+		level, err := ParseLevel(val)
+		if err != nil {
+			return err
+		}
+		cfg.StackTraceLevel = level
+		return nil
+	}
+
+	chKey := strings.TrimPrefix(key, "CHANNELS_")
+	if chKey == key {
+		return fmt.Errorf("Unknown option: %w", InvalidConfigValueErr)
+	}
+
+	chName, opt := cfg.matchEnvChannel(chKey)
+	if chName == "" || opt == "" {
+		return fmt.Errorf("Channel for \"%s\": %w", chKey, UnknownChannelErr)
+	}
+
+	chCfg, ok := cfg.Channels[chName].(ChannelArbitraryConfig)
+	if !ok {
+		return fmt.Errorf(
+			"Channel \"%s\", overriding from env: %w", chName, UnsupportedErr,
+		)
+	}
+
+	switch opt {
+	case "LEVEL":
+		level, err := ParseLevel(val)
+		if err != nil {
+			return err
+		}
+		chCfg.Level = level
+	case "DRIVER":
+		chCfg.Driver = val
+	default:
+		extra := make(map[string]interface{}, len(chCfg.Extra)+1)
+		for k, v := range chCfg.Extra {
+			extra[k] = v
+		}
+
+		extraKey := envOptionName(opt)
+		for k := range extra {
+			if strings.EqualFold(k, strings.ReplaceAll(opt, "_", "")) {
+				extraKey = k
+				break
+			}
+		}
+
+		v, err := parseEnvValue(val, extra[extraKey])
+		if err != nil {
+			return fmt.Errorf("Option \"%s\": %w", extraKey, err)
+		}
+		extra[extraKey] = v
+		chCfg.Extra = extra
+	}
+
+	cfg.Channels[chName] = chCfg
+
+	return nil
+}
+
+// matchEnvChannel splits key into the longest matching channel name
+// and the option.
+func (cfg Config) matchEnvChannel(key string) (string, string) {
+	chName, opt := "", ""
+	for name := range cfg.Channels {
+		envName := envChannelName(name) + "_"
+		if strings.HasPrefix(key, envName) && len(name) > len(chName) {
+			chName, opt = name, strings.TrimPrefix(key, envName)
+		}
+	}
+
+	return chName, opt
+}
+
+func envChannelName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(name))
+}
+
+// envOptionName converts ENABLE_CALLER to enableCaller.
+func envOptionName(opt string) string {
+	parts := strings.Split(strings.ToLower(opt), "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+
+	return strings.Join(parts, "")
+}
+
+func parseEnvValue(val string, current interface{}) (interface{}, error) {
+	switch current.(type) {
+	case string:
+		return val, nil
+	case bool:
+		return strconv.ParseBool(val)
+	case int:
+		return strconv.Atoi(val)
+	case float64:
+		return strconv.ParseFloat(val, 64)
+	case []interface{}:
+		return parseEnvList(val), nil
+	}
+
+	if b, err := strconv.ParseBool(val); err == nil &&
+		(strings.EqualFold(val, "true") || strings.EqualFold(val, "false")) {
+		return b, nil
+	}
+
+	if n, err := strconv.Atoi(val); err == nil {
+		return n, nil
+	}
+
+	if strings.Contains(val, ",") {
+		return parseEnvList(val), nil
+	}
+
+	return val, nil
+}
+
+func parseEnvList(val string) []interface{} {
+	list := []interface{}{}
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,253 @@
+package logman
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	JSONFormat = "json"
+	YAMLFormat = "yaml"
+	TOMLFormat = "toml"
+)
+
+// LoadConfig reads a Config from r encoded in the given format
+// (JSONFormat, YAMLFormat or TOMLFormat). The expected structure is
+//
+//	defaultChannel: stack
+//	level: info
+//	enableCaller: true
+//	stackTraceLevel: error
+//	channels:
+//	  stderr:
+//	    driver: zap
+//	    level: debug
+//	    encoding: console
+//
+// Every channel option except "driver" and "level" goes to
+// ChannelArbitraryConfig.Extra.
+func LoadConfig(r io.Reader, format string) (Config, error) {
+	raw := map[string]interface{}{}
+
+	var err error
+	switch strings.ToLower(format) {
+	case JSONFormat:
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		err = dec.Decode(&raw)
+	case YAMLFormat, "yml":
+		err = yaml.NewDecoder(r).Decode(&raw)
+		if err == io.EOF {
+			err = nil
+		}
+	case TOMLFormat:
+		_, err = toml.NewDecoder(r).Decode(&raw)
+	default:
+		return Config{}, fmt.Errorf(
+			"Format \"%s\": %w", format, UnsupportedErr,
+		)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("Failed to decode config: %w", err)
+	}
+
+	return parseRawConfig(raw)
+}
+
+// LoadConfigFile reads a Config from the file,
+// the format is detected by the file extension.
+func LoadConfigFile(path string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("Failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	format := strings.TrimPrefix(filepath.Ext(path), ".")
+
+	cfg, err := LoadConfig(f, format)
+	if err != nil {
+		return Config{}, fmt.Errorf("Config file \"%s\": %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+func parseRawConfig(raw map[string]interface{}) (Config, error) {
+	cfg := Config{}
+
+	for _, key := range sortedKeys(raw) {
+		val := normalizeRawValue(raw[key])
+
+		switch key {
+		case "defaultChannel":
+			name, ok := val.(string)
+			if !ok {
+				return cfg, keyErr(key, "string expected")
+			}
+			cfg.DefaultChannel = name
+		case "level":
+			level, err := ParseLevelValue(val)
+			if err != nil {
+				return cfg, fmt.Errorf("Key \"%s\": %w", key, err)
+			}
+			cfg.Level = level
+		case "enableCaller":
+			enableCaller, ok := val.(bool)
+			if !ok {
+				return cfg, keyErr(key, "bool expected")
+			}
+			cfg.EnableCaller = enableCaller
+		case "stackTraceLevel":
+			level, err := ParseLevelValue(val)
+			if err != nil {
+				return cfg, fmt.Errorf("Key \"%s\": %w", key, err)
+			}
+			cfg.StackTraceLevel = level
+		case "callerSkip":
+			skip, ok := val.(int)
+			if !ok {
+				return cfg, keyErr(key, "int expected")
+			}
+			cfg.CallerSkip = skip
+		case "channels":
+			rawChs, ok := val.(map[interface{}]interface{})
+			if !ok {
+				return cfg, keyErr(key, "map expected")
+			}
+
+			namedChs := make(map[string]interface{}, len(rawChs))
+			names := make([]string, 0, len(rawChs))
+			for rawName, rawCh := range rawChs {
+				name := fmt.Sprint(rawName)
+				names = append(names, name)
+				namedChs[name] = rawCh
+			}
+			sort.Strings(names)
+
+			chs := ChannelArbitraryConfigs{}
+			for _, name := range names {
+				path := key + "." + name
+
+				chCfg, err := parseRawChannelConfig(path, namedChs[name])
+				if err != nil {
+					return cfg, err
+				}
+				chs[name] = chCfg
+			}
+			cfg = cfg.WithChannels(chs)
+		default:
+			return cfg, keyErr(key, "unknown option")
+		}
+	}
+
+	return cfg, nil
+}
+
+func parseRawChannelConfig(
+	path string,
+	raw interface{},
+) (ChannelArbitraryConfig, error) {
+	chCfg := ChannelArbitraryConfig{}
+
+	opts, ok := raw.(map[interface{}]interface{})
+	if !ok {
+		return chCfg, keyErr(path, "map expected")
+	}
+
+	for rawOpt, val := range opts {
+		opt, ok := rawOpt.(string)
+		if !ok {
+			return chCfg, keyErr(
+				fmt.Sprintf("%s.%v", path, rawOpt), "string key expected",
+			)
+		}
+
+		switch opt {
+		case "driver":
+			driver, ok := val.(string)
+			if !ok {
+				return chCfg, keyErr(path+"."+opt, "string expected")
+			}
+			chCfg.Driver = driver
+		case "level":
+			level, err := ParseLevelValue(val)
+			if err != nil {
+				return chCfg, fmt.Errorf("Key \"%s.%s\": %w", path, opt, err)
+			}
+			chCfg.Level = level
+		default:
+			if chCfg.Extra == nil {
+				chCfg.Extra = map[string]interface{}{}
+			}
+			chCfg.Extra[opt] = val
+		}
+	}
+
+	return chCfg, nil
+}
+
+// normalizeRawValue converts decoded maps to map[interface{}]interface{}
+// and lists to []interface{}, the structures drivers parse Extra from.
+func normalizeRawValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		m := make(map[interface{}]interface{}, len(v))
+		for key, item := range v {
+			m[key] = normalizeRawValue(item)
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := make(map[interface{}]interface{}, len(v))
+		for key, item := range v {
+			m[key] = normalizeRawValue(item)
+		}
+		return m
+	case []map[string]interface{}:
+		l := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			l = append(l, normalizeRawValue(item))
+		}
+		return l
+	case []interface{}:
+		l := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			l = append(l, normalizeRawValue(item))
+		}
+		return l
+	case int64:
+		return int(v)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return int(n)
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	}
+
+	return val
+}
+
+func keyErr(path string, reason string) error {
+	return fmt.Errorf("Key \"%s\": %s: %w", path, reason, InvalidConfigValueErr)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,81 @@
+package logman
+
+import "context"
+
+type contextKey int
+
+const (
+	loggerContextKey contextKey = iota
+	fieldsContextKey
+	recordContextKey
+)
+
+// NewContext returns a copy of ctx carrying the given logger.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, l)
+}
+
+// FromContext returns the logger stored in ctx by NewContext
+// or the current package-level logger if there is none.
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerContextKey).(Logger); ok {
+			return l
+		}
+	}
+
+	return Current()
+}
+
+// ContextWithFields returns a copy of ctx carrying the given fields
+// in addition to the ones already stored in ctx. Drivers attach them
+// to every entry logged with the context.
+func ContextWithFields(ctx context.Context, fields Fields) context.Context {
+	stored := FieldsFromContext(ctx)
+
+	return context.WithValue(
+		ctx,
+		fieldsContextKey,
+		append(append([]Fields{}, stored...), fields),
+	)
+}
+
+// FieldsFromContext returns the fields stored in ctx by ContextWithFields.
+func FieldsFromContext(ctx context.Context) []Fields {
+	if ctx == nil {
+		return nil
+	}
+
+	fields, _ := ctx.Value(fieldsContextKey).([]Fields)
+
+	return fields
+}
+
+// CopyFields returns shallow copies of the fields, for drivers passing
+// them to other goroutines as callers may change the maps after logging.
+func CopyFields(fields []Fields) []Fields {
+	if fields == nil {
+		return nil
+	}
+
+	copied := make([]Fields, len(fields))
+	for i, fieldSet := range fields {
+		copied[i] = make(Fields, len(fieldSet))
+		for k, v := range fieldSet {
+			copied[i][k] = v
+		}
+	}
+
+	return copied
+}
+
+// ContextWithCopiedFields returns ctx with copies of the fields stored
+// in it by ContextWithFields, see CopyFields.
+func ContextWithCopiedFields(ctx context.Context) context.Context {
+	fields := FieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, fieldsContextKey, CopyFields(fields))
+}
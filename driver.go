@@ -6,6 +6,20 @@ type Driver interface {
 	CreateLogger(lm *Logman, loggerCfg ChannelConfig) (Logger, error)
 }
 
+// DependentDriver is implemented by drivers of composite channels
+// which write to other channels. The channels a channel depends on
+// are created before it.
+type DependentDriver interface {
+	Dependencies(loggerCfg ChannelConfig) ([]string, error)
+}
+
+// Initializer is implemented by loggers which need to be initialized
+// once all the channels are created, e.g. to resolve the channels
+// they depend on. Init is called in the order the channels are created.
+type Initializer interface {
+	Init(lm *Logman) error
+}
+
 func RegisterDriver(name string, driver Driver) {
 	if name == "" {
 		panic("logman: Empty driver name passed")
@@ -0,0 +1,216 @@
+package async
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Chekunin/logman"
+)
+
+const DriverName = "async"
+
+type driver struct{}
+
+func (d driver) CreateLogger(
+	lm *logman.Logman,
+	c logman.ChannelConfig,
+) (logman.Logger, error) {
+	cfg, err := parseConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse config: %w", err)
+	}
+
+	return newLogger(cfg, lm)
+}
+func (d driver) Dependencies(c logman.ChannelConfig) ([]string, error) {
+	cfg, err := parseConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse config: %w", err)
+	}
+
+	return []string{cfg.Channel}, nil
+}
+
+// Logger passes entries to the target logger through a bounded queue
+// processed by background workers.
+type Logger struct {
+	cfg    LoggerConfig
+	level  *logman.AtomicLevel
+	queue  *queue
+	target logman.Logger
+}
+
+func newLogger(cfg LoggerConfig, lm *logman.Logman) (*Logger, error) {
+	if err := cfg.setDefaults(lm).validate(lm); err != nil {
+		return nil, fmt.Errorf("Invalid config: %w", err)
+	}
+
+	return &Logger{
+		cfg:   cfg,
+		level: lm.NewChannelLevel(cfg.Level),
+		queue: newQueue(cfg.Options),
+	}, nil
+}
+
+// Wrap returns an async logger writing to the target, the level
+// of the target is used. It has to be closed to stop its workers.
+func Wrap(target logman.Logger, opts Options) (*Logger, error) {
+	if err := opts.setDefaults().validate(); err != nil {
+		return nil, fmt.Errorf("Invalid options: %w", err)
+	}
+
+	return &Logger{
+		queue:  newQueue(opts),
+		target: target,
+	}, nil
+}
+
+func (l *Logger) Debug(msg string, fields ...logman.Fields) {
+	l.Log(logman.DebugLevel, msg, fields...)
+}
+func (l *Logger) Info(msg string, fields ...logman.Fields) {
+	l.Log(logman.InfoLevel, msg, fields...)
+}
+func (l *Logger) Warning(msg string, fields ...logman.Fields) {
+	l.Log(logman.WarningLevel, msg, fields...)
+}
+func (l *Logger) Error(msg string, fields ...logman.Fields) {
+	l.Log(logman.ErrorLevel, msg, fields...)
+}
+func (l *Logger) Critical(msg string, fields ...logman.Fields) {
+	l.Log(logman.CriticalLevel, msg, fields...)
+}
+func (l *Logger) Log(level logman.Level, msg string, fields ...logman.Fields) {
+	l.LogContext(context.Background(), level, msg, fields...)
+}
+func (l *Logger) DebugContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.DebugLevel, msg, fields...)
+}
+func (l *Logger) InfoContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.InfoLevel, msg, fields...)
+}
+func (l *Logger) WarningContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.WarningLevel, msg, fields...)
+}
+func (l *Logger) ErrorContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.ErrorLevel, msg, fields...)
+}
+func (l *Logger) CriticalContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.CriticalLevel, msg, fields...)
+}
+func (l *Logger) LogContext(
+	ctx context.Context,
+	level logman.Level,
+	msg string,
+	fields ...logman.Fields,
+) {
+	if l.Level() < level {
+		return
+	}
+
+	if l.target == nil || l.target.Level() < level {
+		return
+	}
+
+	// the fields are read by the workers while the caller may reuse them
+	l.queue.push(entry{
+		target: l.target,
+		ctx:    logman.ContextWithCopiedFields(ctx),
+		level:  level,
+		msg:    msg,
+		fields: logman.CopyFields(fields),
+	})
+}
+func (l *Logger) Level() logman.Level {
+	if l.level == nil {
+		return l.target.Level()
+	}
+
+	return l.level.Level()
+}
+func (l *Logger) SetLevel(level logman.Level) {
+	if l.level == nil {
+		if s, ok := l.target.(logman.LevelSetter); ok {
+			s.SetLevel(level)
+		}
+		return
+	}
+
+	l.level.SetLevel(level)
+}
+func (l *Logger) OwnLevel() logman.Level {
+	if l.level == nil {
+		if g, ok := l.target.(logman.OwnLevelGetter); ok {
+			return g.OwnLevel()
+		}
+		return l.target.Level()
+	}
+
+	return l.level.OwnLevel()
+}
+func (l *Logger) With(fields logman.Fields) logman.Logger {
+	child := *l
+	if l.target != nil {
+		child.target = l.target.With(logman.CopyFields(
+			[]logman.Fields{fields},
+		)[0])
+	}
+
+	return &child
+}
+
+// Dropped returns the number of entries discarded due to the queue
+// overflow.
+func (l *Logger) Dropped() uint64 {
+	return l.queue.dropped.Load()
+}
+
+// Sync waits for the queued entries to be written and syncs the target.
+func (l *Logger) Sync() error {
+	l.queue.flush()
+
+	if s, ok := l.target.(logman.Syncer); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// Close writes the queued entries and stops the workers. The target
+// isn't closed, entries logged after that are written synchronously.
+func (l *Logger) Close() error {
+	l.queue.close()
+
+	return nil
+}
+
+// Init resolves the target channel once logman created it.
+func (l *Logger) Init(lm *logman.Logman) error {
+	l.target = lm.Channels(l.cfg.Channel)[l.cfg.Channel]
+
+	return nil
+}
+
+func init() {
+	logman.RegisterDriver(DriverName, driver{})
+}
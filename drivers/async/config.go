@@ -0,0 +1,142 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Chekunin/logman"
+)
+
+type OverflowPolicy string
+
+const (
+	// Block waits for a free slot in the queue.
+	Block OverflowPolicy = "block"
+	// DropNewest discards the entry being logged.
+	DropNewest OverflowPolicy = "dropNewest"
+	// DropOldest discards the oldest queued entry.
+	DropOldest OverflowPolicy = "dropOldest"
+	// Sync writes the entry synchronously.
+	Sync OverflowPolicy = "sync"
+)
+
+// Options configure the queue of an async logger.
+type Options struct {
+	QueueSize int
+	Workers   int
+	Overflow  OverflowPolicy
+	// OnFailure is called by the workers when writing an entry
+	// to the target panics, the panic is passed as err.
+	OnFailure func(
+		ctx context.Context,
+		level logman.Level,
+		msg string,
+		err error,
+	)
+}
+
+func (o *Options) setDefaults() *Options {
+	if o.QueueSize == 0 {
+		o.QueueSize = 1024
+	}
+
+	if o.Workers == 0 {
+		o.Workers = 1
+	}
+
+	if o.Overflow == "" {
+		o.Overflow = Block
+	}
+
+	return o
+}
+func (o Options) validate() error {
+	if o.QueueSize < 0 {
+		return fmt.Errorf("Invalid queue size: %d", o.QueueSize)
+	}
+
+	if o.Workers < 0 {
+		return fmt.Errorf("Invalid workers number: %d", o.Workers)
+	}
+
+	switch o.Overflow {
+	case Block, DropNewest, DropOldest, Sync:
+	default:
+		return fmt.Errorf("Invalid overflow policy: %s", o.Overflow)
+	}
+
+	return nil
+}
+
+type LoggerConfig struct {
+	Level   logman.Level
+	Channel string
+	Options
+}
+
+func (c LoggerConfig) DriverName() string {
+	return DriverName
+}
+func (c *LoggerConfig) setDefaults(lm *logman.Logman) *LoggerConfig {
+	c.Options.setDefaults()
+
+	return c
+}
+func (c LoggerConfig) validate(lm *logman.Logman) error {
+	if c.Level > logman.DebugLevel {
+		return fmt.Errorf("Invalid log level: %d", c.Level)
+	}
+
+	if c.Channel == "" {
+		return errors.New("No \"channel\" defined")
+	}
+
+	if _, exists := lm.Config().Channels[c.Channel]; !exists {
+		return fmt.Errorf(
+			"No configuration defined for channel \"%s\"", c.Channel,
+		)
+	}
+
+	return c.Options.validate()
+}
+
+func parseConfig(c logman.ChannelConfig) (LoggerConfig, error) {
+	if cfg, ok := c.(LoggerConfig); ok {
+		return cfg, nil
+	}
+
+	cfg := LoggerConfig{}
+
+	rawCfg, ok := c.(logman.ChannelArbitraryConfig)
+	if !ok {
+		return cfg, errors.New("Invalid config structure")
+	}
+
+	cfg.Level = rawCfg.Level
+
+	for option, val := range rawCfg.Extra {
+		var ok bool
+
+		switch option {
+		case "channel":
+			cfg.Channel, ok = val.(string)
+		case "queueSize":
+			cfg.QueueSize, ok = val.(int)
+		case "workers":
+			cfg.Workers, ok = val.(int)
+		case "overflow":
+			var overflow string
+			overflow, ok = val.(string)
+			cfg.Overflow = OverflowPolicy(overflow)
+		default:
+			return cfg, fmt.Errorf("Unknown option passsed: %s", option)
+		}
+
+		if !ok {
+			return cfg, fmt.Errorf("Failed to parse \"%s\" option", option)
+		}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,155 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Chekunin/logman"
+)
+
+type entry struct {
+	target logman.Logger
+	ctx    context.Context
+	level  logman.Level
+	msg    string
+	fields []logman.Fields
+}
+
+func (e entry) write() {
+	e.target.LogContext(e.ctx, e.level, e.msg, e.fields...)
+}
+
+// queue passes entries to background workers.
+type queue struct {
+	opts    Options
+	entries chan entry
+	dropped atomic.Uint64
+
+	// mu is held for reading while pushing,
+	// so the entries channel is closed after pending pushes.
+	mu     sync.RWMutex
+	closed bool
+
+	pendingMu sync.Mutex
+	pending   int
+	drained   *sync.Cond
+
+	workers sync.WaitGroup
+}
+
+func newQueue(opts Options) *queue {
+	q := &queue{
+		opts:    opts,
+		entries: make(chan entry, opts.QueueSize),
+	}
+	q.drained = sync.NewCond(&q.pendingMu)
+
+	for i := 0; i < opts.Workers; i++ {
+		q.workers.Add(1)
+		go q.work()
+	}
+
+	return q
+}
+
+func (q *queue) push(e entry) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		e.write()
+		return
+	}
+
+	q.addPending(1)
+
+	switch q.opts.Overflow {
+	case DropNewest:
+		select {
+		case q.entries <- e:
+		default:
+			q.dropped.Add(1)
+			q.addPending(-1)
+		}
+	case DropOldest:
+		for {
+			select {
+			case q.entries <- e:
+				return
+			default:
+			}
+
+			select {
+			case <-q.entries:
+				q.dropped.Add(1)
+				q.addPending(-1)
+			default:
+			}
+		}
+	case Sync:
+		select {
+		case q.entries <- e:
+		default:
+			q.addPending(-1)
+			e.write()
+		}
+	default:
+		q.entries <- e
+	}
+}
+
+func (q *queue) work() {
+	defer q.workers.Done()
+
+	for e := range q.entries {
+		q.process(e)
+	}
+}
+
+func (q *queue) process(e entry) {
+	defer q.addPending(-1)
+	// a broken target must not stop the worker
+	defer func() {
+		r := recover()
+		if r != nil && q.opts.OnFailure != nil {
+			q.opts.OnFailure(e.ctx, e.level, e.msg, fmt.Errorf("Panic: %v", r))
+		}
+	}()
+
+	e.write()
+}
+
+func (q *queue) addPending(n int) {
+	q.pendingMu.Lock()
+	q.pending += n
+	if q.pending == 0 {
+		q.drained.Broadcast()
+	}
+	q.pendingMu.Unlock()
+}
+
+// flush waits for the queued entries to be written.
+func (q *queue) flush() {
+	q.pendingMu.Lock()
+	for q.pending > 0 {
+		q.drained.Wait()
+	}
+	q.pendingMu.Unlock()
+}
+
+// close writes the queued entries and stops the workers,
+// entries pushed after that are written synchronously.
+func (q *queue) close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	close(q.entries)
+	q.mu.Unlock()
+
+	q.workers.Wait()
+}
@@ -0,0 +1,122 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Chekunin/logman"
+)
+
+type LoggerConfig struct {
+	Level    logman.Level
+	Path     string
+	Encoding string
+	// MaxSize in megabytes the file is rotated at, unlike
+	// WriterConfig.MaxSize and the maxSize option of NewWriterFromURL
+	// which are in bytes.
+	MaxSize    int
+	Interval   time.Duration
+	MaxBackups int
+	MaxAge     time.Duration
+	Compress   bool
+}
+
+func (c LoggerConfig) DriverName() string {
+	return DriverName
+}
+func (c *LoggerConfig) setDefaults(lm *logman.Logman) *LoggerConfig {
+	if c.Encoding == "" {
+		c.Encoding = "json"
+	}
+
+	return c
+}
+func (c LoggerConfig) validate(_ *logman.Logman) error {
+	if c.Level > logman.DebugLevel {
+		return fmt.Errorf("Invalid log level: %d", c.Level)
+	}
+
+	if c.Path == "" {
+		return errors.New("No \"path\" defined")
+	}
+
+	if c.Encoding != "console" && c.Encoding != "json" {
+		return fmt.Errorf("Invalid encoding: %s", c.Encoding)
+	}
+
+	if c.MaxSize < 0 || c.MaxBackups < 0 || c.Interval < 0 || c.MaxAge < 0 {
+		return errors.New("Negative rotation options")
+	}
+
+	return nil
+}
+func (c LoggerConfig) writerConfig() WriterConfig {
+	return WriterConfig{
+		Path:       c.Path,
+		MaxSize:    int64(c.MaxSize) * 1024 * 1024,
+		Interval:   c.Interval,
+		MaxBackups: c.MaxBackups,
+		MaxAge:     c.MaxAge,
+		Compress:   c.Compress,
+	}
+}
+
+func parseConfig(c logman.ChannelConfig) (LoggerConfig, error) {
+	if cfg, ok := c.(LoggerConfig); ok {
+		return cfg, nil
+	}
+
+	cfg := LoggerConfig{}
+
+	rawCfg, ok := c.(logman.ChannelArbitraryConfig)
+	if !ok {
+		return cfg, errors.New("Invalid config structure")
+	}
+
+	cfg.Level = rawCfg.Level
+
+	for option, val := range rawCfg.Extra {
+		var ok bool
+
+		switch option {
+		case "path":
+			cfg.Path, ok = val.(string)
+		case "encoding":
+			cfg.Encoding, ok = val.(string)
+		case "maxSize":
+			cfg.MaxSize, ok = val.(int)
+		case "maxBackups":
+			cfg.MaxBackups, ok = val.(int)
+		case "compress":
+			cfg.Compress, ok = val.(bool)
+		case "interval", "maxAge":
+			var d time.Duration
+			d, ok = parseDuration(val)
+			if option == "interval" {
+				cfg.Interval = d
+			} else {
+				cfg.MaxAge = d
+			}
+		default:
+			return cfg, fmt.Errorf("Unknown option passsed: %s", option)
+		}
+
+		if !ok {
+			return cfg, fmt.Errorf("Failed to parse \"%s\" option", option)
+		}
+	}
+
+	return cfg, nil
+}
+
+func parseDuration(val interface{}) (time.Duration, bool) {
+	s, ok := val.(string)
+	if !ok {
+		return 0, false
+	}
+
+	d, err := time.ParseDuration(s)
+
+	return d, err == nil
+}
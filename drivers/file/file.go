@@ -0,0 +1,221 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Chekunin/logman"
+)
+
+const DriverName = "file"
+
+type driver struct{}
+
+func (d driver) CreateLogger(
+	lm *logman.Logman,
+	c logman.ChannelConfig,
+) (logman.Logger, error) {
+	cfg, err := parseConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse config: %w", err)
+	}
+
+	return newLogger(cfg, lm)
+}
+
+type logger struct {
+	cfg    LoggerConfig
+	level  *logman.AtomicLevel
+	writer *Writer
+	fields []logman.Fields
+}
+
+func newLogger(cfg LoggerConfig, lm *logman.Logman) (*logger, error) {
+	if err := cfg.setDefaults(lm).validate(lm); err != nil {
+		return nil, fmt.Errorf("Invalid config: %w", err)
+	}
+
+	w, err := NewWriter(cfg.writerConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	return &logger{
+		cfg:    cfg,
+		level:  lm.NewChannelLevel(cfg.Level),
+		writer: w,
+	}, nil
+}
+
+func (l *logger) Debug(msg string, fields ...logman.Fields) {
+	l.Log(logman.DebugLevel, msg, fields...)
+}
+func (l *logger) Info(msg string, fields ...logman.Fields) {
+	l.Log(logman.InfoLevel, msg, fields...)
+}
+func (l *logger) Warning(msg string, fields ...logman.Fields) {
+	l.Log(logman.WarningLevel, msg, fields...)
+}
+func (l *logger) Error(msg string, fields ...logman.Fields) {
+	l.Log(logman.ErrorLevel, msg, fields...)
+}
+func (l *logger) Critical(msg string, fields ...logman.Fields) {
+	l.Log(logman.CriticalLevel, msg, fields...)
+}
+func (l *logger) Log(level logman.Level, msg string, fields ...logman.Fields) {
+	l.LogContext(context.Background(), level, msg, fields...)
+}
+func (l *logger) DebugContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.DebugLevel, msg, fields...)
+}
+func (l *logger) InfoContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.InfoLevel, msg, fields...)
+}
+func (l *logger) WarningContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.WarningLevel, msg, fields...)
+}
+func (l *logger) ErrorContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.ErrorLevel, msg, fields...)
+}
+func (l *logger) CriticalContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.CriticalLevel, msg, fields...)
+}
+func (l *logger) LogContext(
+	ctx context.Context,
+	level logman.Level,
+	msg string,
+	fields ...logman.Fields,
+) {
+	if l.Level() < level {
+		return
+	}
+
+	all := make(
+		[]logman.Fields,
+		0,
+		len(l.fields)+len(logman.FieldsFromContext(ctx))+len(fields)+1,
+	)
+	if rec, exists := logman.RecordFromContext(ctx); exists {
+		all = append(all, rec.Fields())
+	}
+	all = append(all, l.fields...)
+	all = append(all, logman.FieldsFromContext(ctx)...)
+	all = append(all, fields...)
+
+	_, _ = l.writer.Write(l.encode(time.Now(), level, msg, all))
+}
+func (l *logger) Level() logman.Level {
+	return l.level.Level()
+}
+func (l *logger) SetLevel(level logman.Level) {
+	l.level.SetLevel(level)
+}
+func (l *logger) OwnLevel() logman.Level {
+	return l.level.OwnLevel()
+}
+func (l *logger) With(fields logman.Fields) logman.Logger {
+	child := *l
+	child.fields = append(append([]logman.Fields{}, l.fields...), fields)
+
+	return &child
+}
+func (l *logger) Sync() error {
+	return l.writer.Sync()
+}
+func (l *logger) Close() error {
+	return l.writer.Close()
+}
+
+// encode encodes an entry to a line, later fields override earlier ones.
+func (l *logger) encode(
+	ts time.Time,
+	level logman.Level,
+	msg string,
+	fields []logman.Fields,
+) []byte {
+	merged := map[string]interface{}{}
+	for _, fieldSet := range fields {
+		for k, v := range logman.ExpandErrors(fieldSet) {
+			merged[k] = v
+		}
+	}
+
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf := &bytes.Buffer{}
+
+	if l.cfg.Encoding == "console" {
+		buf.WriteString(ts.UTC().Format(time.RFC3339Nano))
+		buf.WriteByte('\t')
+		buf.WriteString(strings.ToUpper(level.String()))
+		buf.WriteByte('\t')
+		buf.WriteString(msg)
+		for _, k := range keys {
+			buf.WriteByte('\t')
+			buf.WriteString(k)
+			buf.WriteByte('=')
+			buf.Write(marshal(merged[k]))
+		}
+		buf.WriteByte('\n')
+
+		return buf.Bytes()
+	}
+
+	buf.WriteString(`{"ts":`)
+	buf.Write(marshal(ts.UTC().Format(time.RFC3339Nano)))
+	buf.WriteString(`,"level":`)
+	buf.Write(marshal(level.String()))
+	buf.WriteString(`,"msg":`)
+	buf.Write(marshal(msg))
+	for _, k := range keys {
+		buf.WriteByte(',')
+		buf.Write(marshal(k))
+		buf.WriteByte(':')
+		buf.Write(marshal(merged[k]))
+	}
+	buf.WriteString("}\n")
+
+	return buf.Bytes()
+}
+
+func marshal(v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("%+v", v))
+	}
+
+	return b
+}
+
+func init() {
+	logman.RegisterDriver(DriverName, driver{})
+}
@@ -0,0 +1,378 @@
+package file
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+const compressSuffix = ".gz"
+
+type WriterConfig struct {
+	// Path of the file, rotated files are placed next to it with a timestamp
+	// added to the name, e.g. app-2006-01-02T15-04-05.000.log.
+	Path string
+	// MaxSize in bytes the file is rotated at, zero disables the rotation.
+	// Note that LoggerConfig.MaxSize of the driver is in megabytes.
+	MaxSize int64
+	// Interval the file is rotated at, aligned to the UTC time,
+	// e.g. 24h rotates at midnight. Zero disables the rotation.
+	Interval time.Duration
+	// MaxBackups is the number of rotated files to keep, zero keeps all.
+	MaxBackups int
+	// MaxAge of rotated files to keep, zero keeps all.
+	MaxAge time.Duration
+	// Compress rotated files with gzip.
+	Compress bool
+}
+
+// Writer is an io.Writer appending to a file and rotating it according
+// to the config. It is safe for concurrent use.
+type Writer struct {
+	cfg WriterConfig
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	cleanupMu sync.Mutex
+	cleanupWg sync.WaitGroup
+}
+
+func NewWriter(cfg WriterConfig) (*Writer, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("No path defined")
+	}
+
+	w := &Writer{cfg: cfg}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// NewWriterFromURL creates a writer from URL like
+// rotate:///var/log/app.log?maxSize=104857600&interval=24h&maxBackups=7
+// &maxAge=168h&compress=true, the scheme is ignored. As in WriterConfig,
+// maxSize is in bytes.
+func NewWriterFromURL(u *url.URL) (*Writer, error) {
+	cfg := WriterConfig{Path: u.Path}
+	if u.Opaque != "" {
+		cfg.Path = u.Opaque
+	}
+
+	var err error
+	for option, vals := range u.Query() {
+		val := vals[len(vals)-1]
+
+		switch option {
+		case "maxSize":
+			cfg.MaxSize, err = strconv.ParseInt(val, 10, 64)
+		case "interval":
+			cfg.Interval, err = time.ParseDuration(val)
+		case "maxBackups":
+			cfg.MaxBackups, err = strconv.Atoi(val)
+		case "maxAge":
+			cfg.MaxAge, err = time.ParseDuration(val)
+		case "compress":
+			cfg.Compress, err = strconv.ParseBool(val)
+		default:
+			return nil, fmt.Errorf("Unknown option passsed: %s", option)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse \"%s\" option: %w", option, err)
+		}
+	}
+
+	return NewWriter(cfg)
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	// the entry is written to the current file if the rotation fails
+	var rotateErr error
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			rotateErr = fmt.Errorf("Failed to rotate file: %w", err)
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+
+	if err == nil {
+		err = rotateErr
+	}
+
+	return n, err
+}
+
+func (w *Writer) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	return w.file.Sync()
+}
+
+// Close closes the file and waits for rotated files to be processed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	w.mu.Unlock()
+
+	w.cleanupWg.Wait()
+
+	return err
+}
+
+// Rotate rotates the file regardless of the config.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.rotate()
+}
+
+func (w *Writer) shouldRotate(size int64) bool {
+	if w.cfg.MaxSize > 0 && w.size > 0 && w.size+size > w.cfg.MaxSize {
+		return true
+	}
+
+	if w.cfg.Interval > 0 {
+		boundary := w.openedAt.Truncate(w.cfg.Interval).Add(w.cfg.Interval)
+		if !time.Now().Before(boundary) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *Writer) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.cfg.Path), 0o755); err != nil {
+		return fmt.Errorf("Failed to create directory: %w", err)
+	}
+
+	f, err := os.OpenFile(
+		w.cfg.Path,
+		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+		0o644,
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to open file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("Failed to stat file: %w", err)
+	}
+
+	w.file = f
+	w.size = info.Size()
+	w.openedAt = time.Now()
+	if w.size > 0 {
+		w.openedAt = info.ModTime()
+	}
+
+	return nil
+}
+
+// rotate renames the file and opens a new one. The current file is kept
+// open until the new one is opened, so writing continues to it if any
+// of the steps fails.
+func (w *Writer) rotate() error {
+	prev := w.file
+
+	if w.size > 0 {
+		err := os.Rename(w.cfg.Path, w.backupName(time.Now()))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	if prev != nil {
+		_ = prev.Close()
+	}
+
+	w.cleanupWg.Add(1)
+	go func() {
+		defer w.cleanupWg.Done()
+		w.cleanup()
+	}()
+
+	return nil
+}
+
+// backupName returns a name for a rotated file which isn't taken
+// by another one, the time is advanced by a millisecond until it's free.
+func (w *Writer) backupName(t time.Time) string {
+	dir, prefix, ext := w.nameParts()
+
+	for {
+		name := filepath.Join(
+			dir,
+			prefix+t.UTC().Format(backupTimeFormat)+ext,
+		)
+		if !exists(name) && !exists(name+compressSuffix) {
+			return name
+		}
+
+		t = t.Add(time.Millisecond)
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+
+	return !errors.Is(err, os.ErrNotExist)
+}
+
+func (w *Writer) nameParts() (dir string, prefix string, ext string) {
+	dir = filepath.Dir(w.cfg.Path)
+	name := filepath.Base(w.cfg.Path)
+	ext = filepath.Ext(name)
+
+	return dir, strings.TrimSuffix(name, ext) + "-", ext
+}
+
+type backup struct {
+	path       string
+	time       time.Time
+	compressed bool
+}
+
+// backups returns the rotated files, the newest first.
+func (w *Writer) backups() ([]backup, error) {
+	dir, prefix, ext := w.nameParts()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []backup{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		name := e.Name()
+		compressed := strings.HasSuffix(name, compressSuffix)
+		ts := strings.TrimSuffix(name, compressSuffix)
+
+		if !strings.HasPrefix(ts, prefix) || !strings.HasSuffix(ts, ext) {
+			continue
+		}
+		ts = strings.TrimSuffix(strings.TrimPrefix(ts, prefix), ext)
+
+		t, err := time.Parse(backupTimeFormat, ts)
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, backup{
+			path:       filepath.Join(dir, name),
+			time:       t,
+			compressed: compressed,
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+
+	return backups, nil
+}
+
+// cleanup removes the rotated files exceeding MaxBackups and MaxAge
+// and compresses the rest if needed.
+func (w *Writer) cleanup() {
+	w.cleanupMu.Lock()
+	defer w.cleanupMu.Unlock()
+
+	backups, err := w.backups()
+	if err != nil {
+		return
+	}
+
+	for n, b := range backups {
+		if (w.cfg.MaxBackups > 0 && n >= w.cfg.MaxBackups) ||
+			(w.cfg.MaxAge > 0 && time.Since(b.time) > w.cfg.MaxAge) {
+			_ = os.Remove(b.path)
+			continue
+		}
+
+		if w.cfg.Compress && !b.compressed {
+			_ = compressFile(b.path)
+		}
+	}
+}
+
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(
+		path+compressSuffix,
+		os.O_CREATE|os.O_WRONLY|os.O_TRUNC,
+		0o644,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			dst.Close()
+			os.Remove(path + compressSuffix)
+		}
+	}()
+
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err != nil {
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
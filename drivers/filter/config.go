@@ -0,0 +1,235 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/Chekunin/logman"
+)
+
+type Action string
+
+const (
+	Include Action = "include"
+	Exclude Action = "exclude"
+)
+
+// Rule matches an entry when all of its conditions set match.
+type Rule struct {
+	Action Action
+	// Message is a regular expression the message has to match.
+	Message string
+	// Field has to be present in the entry fields.
+	Field string
+	// Equals is the value the Field has to be equal to
+	// (compared by the string representation), if set.
+	Equals interface{}
+	// MinLevel is the least severe level matched, e.g. DebugLevel.
+	MinLevel logman.Level
+	// MaxLevel is the most severe level matched, e.g. WarningLevel.
+	MaxLevel logman.Level
+
+	message *regexp.Regexp
+}
+
+func (r *Rule) setDefaults() *Rule {
+	if r.Action == "" {
+		r.Action = Include
+	}
+
+	return r
+}
+func (r Rule) validate() error {
+	if r.Action != Include && r.Action != Exclude {
+		return fmt.Errorf("Invalid action: %s", r.Action)
+	}
+
+	if r.Equals != nil && r.Field == "" {
+		return errors.New("No \"field\" defined for \"equals\"")
+	}
+
+	for _, level := range []logman.Level{r.MinLevel, r.MaxLevel} {
+		if level != logman.NotSet &&
+			(level < logman.CriticalLevel || level > logman.DebugLevel) {
+			return fmt.Errorf("Invalid level: %d", level)
+		}
+	}
+
+	return nil
+}
+
+type LoggerConfig struct {
+	Level   logman.Level
+	Channel string
+	Rules   []Rule
+	// Default is the action for entries not matching any rule.
+	Default Action
+}
+
+func (c LoggerConfig) DriverName() string {
+	return DriverName
+}
+func (c *LoggerConfig) setDefaults(lm *logman.Logman) *LoggerConfig {
+	if c.Default == "" {
+		c.Default = Include
+	}
+
+	for i := range c.Rules {
+		c.Rules[i].setDefaults()
+	}
+
+	return c
+}
+func (c LoggerConfig) validate(lm *logman.Logman) error {
+	if c.Level > logman.DebugLevel {
+		return fmt.Errorf("Invalid log level: %d", c.Level)
+	}
+
+	if c.Channel == "" {
+		return errors.New("No \"channel\" defined")
+	}
+
+	if _, exists := lm.Config().Channels[c.Channel]; !exists {
+		return fmt.Errorf(
+			"No configuration defined for channel \"%s\"", c.Channel,
+		)
+	}
+
+	if c.Default != Include && c.Default != Exclude {
+		return fmt.Errorf("Invalid default action: %s", c.Default)
+	}
+
+	for n, r := range c.Rules {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("Invalid rule #%d: %w", n+1, err)
+		}
+	}
+
+	return nil
+}
+
+// compile compiles the message expressions of the rules.
+func (c *LoggerConfig) compile() error {
+	for n := range c.Rules {
+		if c.Rules[n].Message == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(c.Rules[n].Message)
+		if err != nil {
+			return fmt.Errorf("Invalid message in rule #%d: %w", n+1, err)
+		}
+		c.Rules[n].message = re
+	}
+
+	return nil
+}
+
+func parseConfig(c logman.ChannelConfig) (LoggerConfig, error) {
+	if cfg, ok := c.(LoggerConfig); ok {
+		return cfg, nil
+	}
+
+	cfg := LoggerConfig{}
+
+	rawCfg, ok := c.(logman.ChannelArbitraryConfig)
+	if !ok {
+		return cfg, errors.New("Invalid config structure")
+	}
+
+	cfg.Level = rawCfg.Level
+
+	for option, val := range rawCfg.Extra {
+		if option == "channel" {
+			channel, ok := val.(string)
+			if !ok {
+				return cfg, fmt.Errorf("Failed to parse \"channel\" option")
+			}
+			cfg.Channel = channel
+			continue
+		}
+
+		if option == "default" {
+			action, ok := val.(string)
+			if !ok {
+				return cfg, fmt.Errorf("Failed to parse \"default\" option")
+			}
+			cfg.Default = Action(action)
+			continue
+		}
+
+		if option == "rules" {
+			rawRules, ok := val.([]interface{})
+			if !ok {
+				return cfg, fmt.Errorf(
+					"Invalid structure for \"rules\" option",
+				)
+			}
+
+			for n, rawRule := range rawRules {
+				rule, err := parseRule(rawRule)
+				if err != nil {
+					return cfg, fmt.Errorf(
+						"Failed to parse \"rules\" option #%d: %w", n+1, err,
+					)
+				}
+				cfg.Rules = append(cfg.Rules, rule)
+			}
+			continue
+		}
+
+		return cfg, fmt.Errorf("Unknown option passsed: %s", option)
+	}
+
+	return cfg, nil
+}
+
+func parseRule(raw interface{}) (Rule, error) {
+	rule := Rule{}
+
+	rawRule, ok := raw.(map[interface{}]interface{})
+	if !ok {
+		return rule, errors.New("Invalid structure")
+	}
+
+	for rawOpt, v := range rawRule {
+		opt, ok := rawOpt.(string)
+		if !ok {
+			return rule, errors.New("Not string key used")
+		}
+
+		switch opt {
+		case "action":
+			var action string
+			action, ok = v.(string)
+			rule.Action = Action(action)
+		case "message":
+			rule.Message, ok = v.(string)
+		case "field":
+			rule.Field, ok = v.(string)
+		case "equals":
+			rule.Equals = v
+		case "minLevel", "maxLevel":
+			level, err := logman.ParseLevelValue(v)
+			if err != nil {
+				return rule, fmt.Errorf("Failed to parse \"%s\": %w", opt, err)
+			}
+
+			if opt == "minLevel" {
+				rule.MinLevel = level
+			} else {
+				rule.MaxLevel = level
+			}
+			ok = true
+		default:
+			return rule, fmt.Errorf("Unknown option passsed: %s", opt)
+		}
+
+		if !ok {
+			return rule, fmt.Errorf("Failed to parse \"%s\"", opt)
+		}
+	}
+
+	return rule, nil
+}
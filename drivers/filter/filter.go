@@ -0,0 +1,238 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Chekunin/logman"
+)
+
+const DriverName = "filter"
+
+type driver struct{}
+
+func (d driver) CreateLogger(
+	lm *logman.Logman,
+	c logman.ChannelConfig,
+) (logman.Logger, error) {
+	cfg, err := parseConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse config: %w", err)
+	}
+
+	return newLogger(cfg, lm)
+}
+func (d driver) Dependencies(c logman.ChannelConfig) ([]string, error) {
+	cfg, err := parseConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse config: %w", err)
+	}
+
+	return []string{cfg.Channel}, nil
+}
+
+type logger struct {
+	cfg    LoggerConfig
+	level  *logman.AtomicLevel
+	fields []logman.Fields
+	target logman.Logger
+}
+
+func newLogger(cfg LoggerConfig, lm *logman.Logman) (*logger, error) {
+	cfg.Rules = append([]Rule{}, cfg.Rules...)
+
+	if err := cfg.setDefaults(lm).validate(lm); err != nil {
+		return nil, fmt.Errorf("Invalid config: %w", err)
+	}
+
+	if err := cfg.compile(); err != nil {
+		return nil, fmt.Errorf("Invalid config: %w", err)
+	}
+
+	return &logger{
+		cfg:   cfg,
+		level: lm.NewChannelLevel(cfg.Level),
+	}, nil
+}
+
+func (l *logger) Debug(msg string, fields ...logman.Fields) {
+	l.Log(logman.DebugLevel, msg, fields...)
+}
+func (l *logger) Info(msg string, fields ...logman.Fields) {
+	l.Log(logman.InfoLevel, msg, fields...)
+}
+func (l *logger) Warning(msg string, fields ...logman.Fields) {
+	l.Log(logman.WarningLevel, msg, fields...)
+}
+func (l *logger) Error(msg string, fields ...logman.Fields) {
+	l.Log(logman.ErrorLevel, msg, fields...)
+}
+func (l *logger) Critical(msg string, fields ...logman.Fields) {
+	l.Log(logman.CriticalLevel, msg, fields...)
+}
+func (l *logger) Log(level logman.Level, msg string, fields ...logman.Fields) {
+	l.LogContext(context.Background(), level, msg, fields...)
+}
+func (l *logger) DebugContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.DebugLevel, msg, fields...)
+}
+func (l *logger) InfoContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.InfoLevel, msg, fields...)
+}
+func (l *logger) WarningContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.WarningLevel, msg, fields...)
+}
+func (l *logger) ErrorContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.ErrorLevel, msg, fields...)
+}
+func (l *logger) CriticalContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.CriticalLevel, msg, fields...)
+}
+func (l *logger) LogContext(
+	ctx context.Context,
+	level logman.Level,
+	msg string,
+	fields ...logman.Fields,
+) {
+	if l.Level() < level {
+		return
+	}
+
+	if l.target == nil || l.target.Level() < level {
+		return
+	}
+
+	if !l.allow(ctx, level, msg, fields) {
+		return
+	}
+
+	l.target.LogContext(ctx, level, msg, fields...)
+}
+func (l *logger) Level() logman.Level {
+	return l.level.Level()
+}
+func (l *logger) SetLevel(level logman.Level) {
+	l.level.SetLevel(level)
+}
+func (l *logger) OwnLevel() logman.Level {
+	return l.level.OwnLevel()
+}
+func (l *logger) With(fields logman.Fields) logman.Logger {
+	child := *l
+	child.fields = append(append([]logman.Fields{}, l.fields...), fields)
+	if l.target != nil {
+		child.target = l.target.With(fields)
+	}
+
+	return &child
+}
+func (l *logger) Sync() error {
+	if s, ok := l.target.(logman.Syncer); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// allow applies the action of the first rule matching the entry.
+func (l *logger) allow(
+	ctx context.Context,
+	level logman.Level,
+	msg string,
+	fields []logman.Fields,
+) bool {
+	all := make(
+		[]logman.Fields,
+		0,
+		len(l.fields)+len(logman.FieldsFromContext(ctx))+len(fields),
+	)
+	all = append(all, l.fields...)
+	all = append(all, logman.FieldsFromContext(ctx)...)
+	all = append(all, fields...)
+
+	for _, r := range l.cfg.Rules {
+		if r.matches(level, msg, all) {
+			return r.Action == Include
+		}
+	}
+
+	return l.cfg.Default == Include
+}
+
+// Init resolves the target channel once logman created it.
+func (l *logger) Init(lm *logman.Logman) error {
+	l.target = lm.Channels(l.cfg.Channel)[l.cfg.Channel]
+
+	return nil
+}
+
+func (r Rule) matches(
+	level logman.Level,
+	msg string,
+	fields []logman.Fields,
+) bool {
+	// levels are ordered from the most severe one
+	if r.MinLevel != logman.NotSet && level > r.MinLevel {
+		return false
+	}
+
+	if r.MaxLevel != logman.NotSet && level < r.MaxLevel {
+		return false
+	}
+
+	if r.message != nil && !r.message.MatchString(msg) {
+		return false
+	}
+
+	if r.Field != "" {
+		val, exists := lookupField(fields, r.Field)
+		if !exists {
+			return false
+		}
+
+		if r.Equals != nil && fmt.Sprint(val) != fmt.Sprint(r.Equals) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lookupField returns the last value of the field,
+// as later fields override earlier ones.
+func lookupField(
+	fields []logman.Fields,
+	name string,
+) (interface{}, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if val, exists := fields[i][name]; exists {
+			return val, true
+		}
+	}
+
+	return nil, false
+}
+
+func init() {
+	logman.RegisterDriver(DriverName, driver{})
+}
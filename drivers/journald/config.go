@@ -0,0 +1,82 @@
+package journald
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Chekunin/logman"
+)
+
+const DefaultSocketPath = "/run/systemd/journal/socket"
+
+type LoggerConfig struct {
+	Level      logman.Level
+	SocketPath string
+	// SyslogIdentifier is sent as SYSLOG_IDENTIFIER, the executable name
+	// is used by default.
+	SyslogIdentifier string
+	// EnableCaller adds CODE_FILE, CODE_LINE and CODE_FUNC fields
+	// when logman doesn't capture the caller itself,
+	// see logman.Config.EnableCaller.
+	EnableCaller bool
+}
+
+func (c LoggerConfig) DriverName() string {
+	return DriverName
+}
+func (c *LoggerConfig) setDefaults(lm *logman.Logman) *LoggerConfig {
+	if c.SocketPath == "" {
+		c.SocketPath = DefaultSocketPath
+	}
+
+	if c.SyslogIdentifier == "" {
+		c.SyslogIdentifier = filepath.Base(os.Args[0])
+	}
+
+	return c
+}
+func (c LoggerConfig) validate(_ *logman.Logman) error {
+	if c.Level > logman.DebugLevel {
+		return fmt.Errorf("Invalid log level: %d", c.Level)
+	}
+
+	return nil
+}
+
+func parseConfig(c logman.ChannelConfig) (LoggerConfig, error) {
+	if cfg, ok := c.(LoggerConfig); ok {
+		return cfg, nil
+	}
+
+	cfg := LoggerConfig{}
+
+	rawCfg, ok := c.(logman.ChannelArbitraryConfig)
+	if !ok {
+		return cfg, errors.New("Invalid config structure")
+	}
+
+	cfg.Level = rawCfg.Level
+
+	for option, val := range rawCfg.Extra {
+		var ok bool
+
+		switch option {
+		case "socketPath":
+			cfg.SocketPath, ok = val.(string)
+		case "syslogIdentifier":
+			cfg.SyslogIdentifier, ok = val.(string)
+		case "enableCaller":
+			cfg.EnableCaller, ok = val.(bool)
+		default:
+			return cfg, fmt.Errorf("Unknown option passsed: %s", option)
+		}
+
+		if !ok {
+			return cfg, fmt.Errorf("Failed to parse \"%s\" option", option)
+		}
+	}
+
+	return cfg, nil
+}
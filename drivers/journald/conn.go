@@ -0,0 +1,75 @@
+//go:build linux
+
+package journald
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+type conn struct {
+	conn *net.UnixConn
+	addr *net.UnixAddr
+}
+
+func newConn(socketPath string) (*conn, error) {
+	if _, err := os.Stat(socketPath); err != nil {
+		return nil, fmt.Errorf(
+			"Journal socket %s is not available: %w", socketPath, err,
+		)
+	}
+
+	c, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open journal connection: %w", err)
+	}
+
+	return &conn{
+		conn: c,
+		addr: &net.UnixAddr{Name: socketPath, Net: "unixgram"},
+	}, nil
+}
+
+// write sends the entry as a datagram, entries too large for a datagram
+// are passed through a temporary file descriptor.
+func (c *conn) write(data []byte) error {
+	_, err := c.conn.WriteToUnix(data, c.addr)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, syscall.EMSGSIZE) && !errors.Is(err, syscall.ENOBUFS) {
+		return err
+	}
+
+	return c.writeFile(data)
+}
+
+func (c *conn) writeFile(data []byte) error {
+	f, err := os.CreateTemp("/dev/shm", "logman-journal-")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := os.Remove(f.Name()); err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+
+	_, _, err = c.conn.WriteMsgUnix(
+		nil, syscall.UnixRights(int(f.Fd())), c.addr,
+	)
+
+	return err
+}
+
+func (c *conn) close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,73 @@
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// fieldName converts a key to a journal field name: uppercase letters,
+// digits and underscores, not starting with an underscore or a digit.
+func fieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		}
+		return '_'
+	}, key)
+
+	name = strings.TrimLeft(name, "_")
+	if name == "" {
+		return ""
+	}
+
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "F_" + name
+	}
+
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	return name
+}
+
+func fieldValue(v interface{}) string {
+	if err, ok := v.(error); ok {
+		return err.Error()
+	}
+
+	return fmt.Sprint(v)
+}
+
+// encode serializes the fields in the native journal protocol,
+// values containing newlines are length-prefixed.
+func encode(fields map[string]string) []byte {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b := &bytes.Buffer{}
+	for _, name := range names {
+		value := fields[name]
+
+		b.WriteString(name)
+		if strings.Contains(value, "\n") {
+			b.WriteByte('\n')
+			_ = binary.Write(b, binary.LittleEndian, uint64(len(value)))
+		} else {
+			b.WriteByte('=')
+		}
+		b.WriteString(value)
+		b.WriteByte('\n')
+	}
+
+	return b.Bytes()
+}
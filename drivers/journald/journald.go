@@ -0,0 +1,179 @@
+package journald
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Chekunin/logman"
+)
+
+const DriverName = "journald"
+
+// priorities maps levels to syslog priorities used by journal.
+var priorities = map[logman.Level]string{
+	logman.CriticalLevel: "2",
+	logman.ErrorLevel:    "3",
+	logman.WarningLevel:  "4",
+	logman.InfoLevel:     "6",
+	logman.DebugLevel:    "7",
+}
+
+type driver struct{}
+
+func (d driver) CreateLogger(
+	lm *logman.Logman,
+	c logman.ChannelConfig,
+) (logman.Logger, error) {
+	cfg, err := parseConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse config: %w", err)
+	}
+
+	return newLogger(cfg, lm)
+}
+
+type logger struct {
+	cfg    LoggerConfig
+	level  *logman.AtomicLevel
+	conn   *conn
+	fields []logman.Fields
+}
+
+func newLogger(cfg LoggerConfig, lm *logman.Logman) (*logger, error) {
+	if err := cfg.setDefaults(lm).validate(lm); err != nil {
+		return nil, fmt.Errorf("Invalid config: %w", err)
+	}
+
+	c, err := newConn(cfg.SocketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logger{
+		cfg:   cfg,
+		level: lm.NewChannelLevel(cfg.Level),
+		conn:  c,
+	}, nil
+}
+
+func (l *logger) Debug(msg string, fields ...logman.Fields) {
+	l.Log(logman.DebugLevel, msg, fields...)
+}
+func (l *logger) Info(msg string, fields ...logman.Fields) {
+	l.Log(logman.InfoLevel, msg, fields...)
+}
+func (l *logger) Warning(msg string, fields ...logman.Fields) {
+	l.Log(logman.WarningLevel, msg, fields...)
+}
+func (l *logger) Error(msg string, fields ...logman.Fields) {
+	l.Log(logman.ErrorLevel, msg, fields...)
+}
+func (l *logger) Critical(msg string, fields ...logman.Fields) {
+	l.Log(logman.CriticalLevel, msg, fields...)
+}
+func (l *logger) Log(level logman.Level, msg string, fields ...logman.Fields) {
+	l.LogContext(context.Background(), level, msg, fields...)
+}
+func (l *logger) DebugContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.DebugLevel, msg, fields...)
+}
+func (l *logger) InfoContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.InfoLevel, msg, fields...)
+}
+func (l *logger) WarningContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.WarningLevel, msg, fields...)
+}
+func (l *logger) ErrorContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.ErrorLevel, msg, fields...)
+}
+func (l *logger) CriticalContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.CriticalLevel, msg, fields...)
+}
+func (l *logger) LogContext(
+	ctx context.Context,
+	level logman.Level,
+	msg string,
+	fields ...logman.Fields,
+) {
+	if l.Level() < level {
+		return
+	}
+
+	entry := map[string]string{}
+	for _, fieldSets := range [][]logman.Fields{
+		l.fields,
+		logman.FieldsFromContext(ctx),
+		fields,
+	} {
+		for _, fieldSet := range fieldSets {
+			for k, v := range logman.ExpandErrors(fieldSet) {
+				if name := fieldName(k); name != "" {
+					entry[name] = fieldValue(v)
+				}
+			}
+		}
+	}
+
+	rec, exists := logman.RecordFromContext(ctx)
+	if !exists && l.cfg.EnableCaller {
+		rec = logman.CaptureRecord(0, false)
+	}
+
+	if rec.HasCaller() {
+		entry["CODE_FILE"] = rec.Caller.File
+		entry["CODE_LINE"] = strconv.Itoa(rec.Caller.Line)
+		entry["CODE_FUNC"] = rec.Caller.Function
+	}
+	if rec.Stack != "" {
+		entry["STACKTRACE"] = rec.Stack
+	}
+
+	entry["MESSAGE"] = msg
+	entry["PRIORITY"] = priorities[level]
+	entry["SYSLOG_IDENTIFIER"] = l.cfg.SyslogIdentifier
+
+	_ = l.conn.write(encode(entry))
+}
+func (l *logger) Level() logman.Level {
+	return l.level.Level()
+}
+func (l *logger) SetLevel(level logman.Level) {
+	l.level.SetLevel(level)
+}
+func (l *logger) OwnLevel() logman.Level {
+	return l.level.OwnLevel()
+}
+func (l *logger) With(fields logman.Fields) logman.Logger {
+	child := *l
+	child.fields = append(append([]logman.Fields{}, l.fields...), fields)
+
+	return &child
+}
+func (l *logger) Close() error {
+	return l.conn.close()
+}
+
+func init() {
+	logman.RegisterDriver(DriverName, driver{})
+}
@@ -0,0 +1,195 @@
+//go:build linux
+
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Chekunin/logman"
+)
+
+// listen creates a socket standing in for the journal socket.
+func listen(t *testing.T) (*net.UnixConn, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "socket")
+	c, err := net.ListenUnixgram("unixgram", &net.UnixAddr{
+		Name: path,
+		Net:  "unixgram",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c, path
+}
+
+// read receives an entry, reading it from the passed file descriptor
+// if there's one.
+func read(t *testing.T, c *net.UnixConn) []byte {
+	t.Helper()
+
+	buf := make([]byte, 1<<16)
+	oob := make([]byte, syscall.CmsgSpace(4))
+
+	_ = c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, oobn, _, _, err := c.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if oobn == 0 {
+		return buf[:n]
+	}
+
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil {
+		t.Fatal(err)
+	}
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := os.NewFile(uintptr(fds[0]), "entry")
+	defer f.Close()
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+// decode parses an entry encoded in the native journal protocol.
+func decode(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	fields := map[string]string{}
+	for len(data) > 0 {
+		end := bytes.IndexAny(data, "=\n")
+		if end < 0 {
+			t.Fatalf("Invalid entry: %q", data)
+		}
+		name := string(data[:end])
+
+		if data[end] == '=' {
+			data = data[end+1:]
+			end = bytes.IndexByte(data, '\n')
+			if end < 0 {
+				t.Fatalf("Invalid value of %s", name)
+			}
+			fields[name] = string(data[:end])
+			data = data[end+1:]
+			continue
+		}
+
+		data = data[end+1:]
+		if len(data) < 8 {
+			t.Fatalf("Invalid length of %s", name)
+		}
+		size := binary.LittleEndian.Uint64(data)
+		data = data[8:]
+		if uint64(len(data)) < size+1 || data[size] != '\n' {
+			t.Fatalf("Invalid value of %s", name)
+		}
+		fields[name] = string(data[:size])
+		data = data[size+1:]
+	}
+
+	return fields
+}
+
+func TestEntry(t *testing.T) {
+	c, path := listen(t)
+
+	l, err := newLogger(LoggerConfig{
+		Level:            logman.DebugLevel,
+		SocketPath:       path,
+		SyslogIdentifier: "app",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	l.With(logman.Fields{"request-id": 42}).Warning(
+		"disk is full",
+		logman.Fields{
+			"path":    "/var/log",
+			"details": "first line\nsecond line",
+			"error":   errors.New("no space left"),
+		},
+	)
+
+	fields := decode(t, read(t, c))
+
+	expected := map[string]string{
+		"MESSAGE":           "disk is full",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "app",
+		"REQUEST_ID":        "42",
+		"PATH":              "/var/log",
+		"DETAILS":           "first line\nsecond line",
+		"ERROR":             "no space left",
+	}
+	for name, value := range expected {
+		if fields[name] != value {
+			t.Errorf("%s = %q, expected %q", name, fields[name], value)
+		}
+	}
+}
+
+func TestLargeEntry(t *testing.T) {
+	c, path := listen(t)
+
+	// the default limit of datagrams is lower than that
+	msg := strings.Repeat("x", 1<<20)
+
+	l, err := newLogger(LoggerConfig{
+		Level:      logman.DebugLevel,
+		SocketPath: path,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	l.Info(msg)
+
+	fields := decode(t, read(t, c))
+	if fields["MESSAGE"] != msg {
+		t.Errorf("MESSAGE of %d bytes expected", len(msg))
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	tests := map[string]string{
+		"user.id":    "USER_ID",
+		"_private":   "PRIVATE",
+		"2fa":        "F_2FA",
+		"MESSAGE_ID": "MESSAGE_ID",
+		"___":        "",
+	}
+
+	for key, expected := range tests {
+		if name := fieldName(key); name != expected {
+			t.Errorf("fieldName(%q) = %q, expected %q", key, name, expected)
+		}
+	}
+}
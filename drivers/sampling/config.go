@@ -0,0 +1,153 @@
+package sampling
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Chekunin/logman"
+)
+
+type LoggerConfig struct {
+	Level   logman.Level
+	Channel string
+	// Tick is the period the sampling counters are reset at.
+	Tick time.Duration
+	// First entries with the same level and message are logged
+	// every tick, zero disables the sampling.
+	First int
+	// Thereafter every Thereafter-th entry is logged,
+	// zero drops all of them.
+	Thereafter int
+	// Rate limits logged entries per second, zero disables the limit.
+	Rate float64
+	// Burst is the number of entries which can exceed the rate at once.
+	Burst int
+	// SummaryInterval is the period of reporting suppressed entries.
+	SummaryInterval time.Duration
+	SummaryLevel    logman.Level
+}
+
+func (c LoggerConfig) DriverName() string {
+	return DriverName
+}
+func (c *LoggerConfig) setDefaults(lm *logman.Logman) *LoggerConfig {
+	if c.Tick == 0 {
+		c.Tick = time.Second
+	}
+
+	if c.Burst == 0 && c.Rate > 0 {
+		c.Burst = int(c.Rate)
+		if float64(c.Burst) < c.Rate {
+			c.Burst++
+		}
+	}
+
+	if c.SummaryInterval == 0 {
+		c.SummaryInterval = 10 * time.Second
+	}
+
+	if c.SummaryLevel == logman.NotSet {
+		c.SummaryLevel = logman.WarningLevel
+	}
+
+	return c
+}
+func (c LoggerConfig) validate(lm *logman.Logman) error {
+	if c.Level > logman.DebugLevel {
+		return fmt.Errorf("Invalid log level: %d", c.Level)
+	}
+
+	if c.SummaryLevel < logman.CriticalLevel ||
+		c.SummaryLevel > logman.DebugLevel {
+		return fmt.Errorf("Invalid summary level: %d", c.SummaryLevel)
+	}
+
+	if c.Channel == "" {
+		return errors.New("No \"channel\" defined")
+	}
+
+	if _, exists := lm.Config().Channels[c.Channel]; !exists {
+		return fmt.Errorf(
+			"No configuration defined for channel \"%s\"", c.Channel,
+		)
+	}
+
+	if c.Tick < 0 || c.SummaryInterval < 0 {
+		return errors.New("Negative intervals")
+	}
+
+	if c.First < 0 || c.Thereafter < 0 || c.Rate < 0 || c.Burst < 0 {
+		return errors.New("Negative sampling options")
+	}
+
+	return nil
+}
+
+func parseConfig(c logman.ChannelConfig) (LoggerConfig, error) {
+	if cfg, ok := c.(LoggerConfig); ok {
+		return cfg, nil
+	}
+
+	cfg := LoggerConfig{}
+
+	rawCfg, ok := c.(logman.ChannelArbitraryConfig)
+	if !ok {
+		return cfg, errors.New("Invalid config structure")
+	}
+
+	cfg.Level = rawCfg.Level
+
+	for option, val := range rawCfg.Extra {
+		var ok bool
+
+		switch option {
+		case "channel":
+			cfg.Channel, ok = val.(string)
+		case "tick":
+			cfg.Tick, ok = parseDuration(val)
+		case "first":
+			cfg.First, ok = val.(int)
+		case "thereafter":
+			cfg.Thereafter, ok = val.(int)
+		case "rate":
+			switch v := val.(type) {
+			case int:
+				cfg.Rate, ok = float64(v), true
+			case float64:
+				cfg.Rate, ok = v, true
+			}
+		case "burst":
+			cfg.Burst, ok = val.(int)
+		case "summaryInterval":
+			cfg.SummaryInterval, ok = parseDuration(val)
+		case "summaryLevel":
+			level, err := logman.ParseLevelValue(val)
+			if err != nil {
+				return cfg, fmt.Errorf(
+					"Failed to parse \"%s\" option: %w", option, err,
+				)
+			}
+			cfg.SummaryLevel, ok = level, true
+		default:
+			return cfg, fmt.Errorf("Unknown option passsed: %s", option)
+		}
+
+		if !ok {
+			return cfg, fmt.Errorf("Failed to parse \"%s\" option", option)
+		}
+	}
+
+	return cfg, nil
+}
+
+func parseDuration(val interface{}) (time.Duration, bool) {
+	s, ok := val.(string)
+	if !ok {
+		return 0, false
+	}
+
+	d, err := time.ParseDuration(s)
+
+	return d, err == nil
+}
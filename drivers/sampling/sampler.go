@@ -0,0 +1,90 @@
+package sampling
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Chekunin/logman"
+)
+
+type sampleKey struct {
+	level logman.Level
+	msg   string
+}
+
+// sampler decides which entries are logged and counts the suppressed ones.
+type sampler struct {
+	cfg LoggerConfig
+
+	mu         sync.Mutex
+	tickEnd    time.Time
+	counts     map[sampleKey]int
+	tokens     float64
+	refilledAt time.Time
+
+	sampled     uint64
+	rateLimited uint64
+}
+
+func newSampler(cfg LoggerConfig) *sampler {
+	now := time.Now()
+
+	return &sampler{
+		cfg:        cfg,
+		tickEnd:    now.Add(cfg.Tick),
+		counts:     map[sampleKey]int{},
+		tokens:     float64(cfg.Burst),
+		refilledAt: now,
+	}
+}
+
+func (s *sampler) allow(level logman.Level, msg string) bool {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cfg.First > 0 {
+		if !now.Before(s.tickEnd) {
+			s.counts = map[sampleKey]int{}
+			s.tickEnd = now.Add(s.cfg.Tick)
+		}
+
+		key := sampleKey{level: level, msg: msg}
+		s.counts[key]++
+
+		n := s.counts[key]
+		if n > s.cfg.First &&
+			(s.cfg.Thereafter == 0 || (n-s.cfg.First)%s.cfg.Thereafter != 0) {
+			s.sampled++
+			return false
+		}
+	}
+
+	if s.cfg.Rate > 0 {
+		s.tokens += now.Sub(s.refilledAt).Seconds() * s.cfg.Rate
+		if s.tokens > float64(s.cfg.Burst) {
+			s.tokens = float64(s.cfg.Burst)
+		}
+		s.refilledAt = now
+
+		if s.tokens < 1 {
+			s.rateLimited++
+			return false
+		}
+		s.tokens--
+	}
+
+	return true
+}
+
+// reset returns the numbers of suppressed entries and resets them.
+func (s *sampler) reset() (sampled uint64, rateLimited uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sampled, rateLimited = s.sampled, s.rateLimited
+	s.sampled, s.rateLimited = 0, 0
+
+	return sampled, rateLimited
+}
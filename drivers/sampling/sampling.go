@@ -0,0 +1,238 @@
+package sampling
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Chekunin/logman"
+)
+
+const DriverName = "sampling"
+
+type driver struct{}
+
+func (d driver) CreateLogger(
+	lm *logman.Logman,
+	c logman.ChannelConfig,
+) (logman.Logger, error) {
+	cfg, err := parseConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse config: %w", err)
+	}
+
+	return newLogger(cfg, lm)
+}
+func (d driver) Dependencies(c logman.ChannelConfig) ([]string, error) {
+	cfg, err := parseConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse config: %w", err)
+	}
+
+	return []string{cfg.Channel}, nil
+}
+
+// shared is the state shared by a logger and its children.
+type shared struct {
+	cfg     LoggerConfig
+	sampler *sampler
+	// target is the channel without the fields passed to With,
+	// the summaries are reported to it.
+	target logman.Logger
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stop      chan struct{}
+	stopped   chan struct{}
+}
+
+type logger struct {
+	*shared
+	level  *logman.AtomicLevel
+	target logman.Logger
+}
+
+func newLogger(cfg LoggerConfig, lm *logman.Logman) (*logger, error) {
+	if err := cfg.setDefaults(lm).validate(lm); err != nil {
+		return nil, fmt.Errorf("Invalid config: %w", err)
+	}
+
+	return &logger{
+		shared: &shared{
+			cfg:     cfg,
+			sampler: newSampler(cfg),
+			stop:    make(chan struct{}),
+			stopped: make(chan struct{}),
+		},
+		level: lm.NewChannelLevel(cfg.Level),
+	}, nil
+}
+
+func (l *logger) Debug(msg string, fields ...logman.Fields) {
+	l.Log(logman.DebugLevel, msg, fields...)
+}
+func (l *logger) Info(msg string, fields ...logman.Fields) {
+	l.Log(logman.InfoLevel, msg, fields...)
+}
+func (l *logger) Warning(msg string, fields ...logman.Fields) {
+	l.Log(logman.WarningLevel, msg, fields...)
+}
+func (l *logger) Error(msg string, fields ...logman.Fields) {
+	l.Log(logman.ErrorLevel, msg, fields...)
+}
+func (l *logger) Critical(msg string, fields ...logman.Fields) {
+	l.Log(logman.CriticalLevel, msg, fields...)
+}
+func (l *logger) Log(level logman.Level, msg string, fields ...logman.Fields) {
+	l.LogContext(context.Background(), level, msg, fields...)
+}
+func (l *logger) DebugContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.DebugLevel, msg, fields...)
+}
+func (l *logger) InfoContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.InfoLevel, msg, fields...)
+}
+func (l *logger) WarningContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.WarningLevel, msg, fields...)
+}
+func (l *logger) ErrorContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.ErrorLevel, msg, fields...)
+}
+func (l *logger) CriticalContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.CriticalLevel, msg, fields...)
+}
+func (l *logger) LogContext(
+	ctx context.Context,
+	level logman.Level,
+	msg string,
+	fields ...logman.Fields,
+) {
+	if l.Level() < level {
+		return
+	}
+
+	if l.target == nil || l.target.Level() < level {
+		return
+	}
+
+	if !l.sampler.allow(level, msg) {
+		return
+	}
+
+	l.target.LogContext(ctx, level, msg, fields...)
+}
+func (l *logger) Level() logman.Level {
+	return l.level.Level()
+}
+func (l *logger) SetLevel(level logman.Level) {
+	l.level.SetLevel(level)
+}
+func (l *logger) OwnLevel() logman.Level {
+	return l.level.OwnLevel()
+}
+func (l *logger) With(fields logman.Fields) logman.Logger {
+	child := *l
+	if l.target != nil {
+		child.target = l.target.With(fields)
+	}
+
+	return &child
+}
+func (l *logger) Sync() error {
+	if s, ok := l.target.(logman.Syncer); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
+
+// Init resolves the target channel once logman created it
+// and starts reporting summaries.
+func (l *logger) Init(lm *logman.Logman) error {
+	l.target = lm.Channels(l.cfg.Channel)[l.cfg.Channel]
+	l.shared.target = l.target
+
+	l.startOnce.Do(func() {
+		go l.reportSummaries()
+	})
+
+	return nil
+}
+
+// Close stops reporting summaries, the last one is reported immediately.
+// The target isn't closed.
+func (l *logger) Close() error {
+	// nothing to wait for if reporting wasn't started
+	l.startOnce.Do(func() {
+		close(l.stopped)
+	})
+	l.stopOnce.Do(func() {
+		close(l.stop)
+	})
+	<-l.stopped
+
+	return nil
+}
+
+func (sh *shared) reportSummaries() {
+	defer close(sh.stopped)
+
+	ticker := time.NewTicker(sh.cfg.SummaryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sh.reportSummary()
+		case <-sh.stop:
+			sh.reportSummary()
+			return
+		}
+	}
+}
+
+func (sh *shared) reportSummary() {
+	sampled, rateLimited := sh.sampler.reset()
+	if sampled+rateLimited == 0 {
+		return
+	}
+
+	if sh.target == nil {
+		return
+	}
+
+	sh.target.Log(
+		sh.cfg.SummaryLevel,
+		fmt.Sprintf("Suppressed %d messages", sampled+rateLimited),
+		logman.Fields{
+			"channel":     sh.cfg.Channel,
+			"sampled":     sampled,
+			"rateLimited": rateLimited,
+		},
+	)
+}
+
+func init() {
+	logman.RegisterDriver(DriverName, driver{})
+}
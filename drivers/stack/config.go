@@ -10,6 +10,15 @@ import (
 type ChannelConfig struct {
 	Name          string
 	DisableBubble bool
+	// MinLevel is the least severe level passed to the channel,
+	// e.g. DebugLevel. Any level if not set.
+	MinLevel logman.Level
+	// MaxLevel is the most severe level passed to the channel,
+	// e.g. InfoLevel. Any level if not set.
+	MaxLevel logman.Level
+	// Async passes entries to the channel through a queue
+	// processed in background.
+	Async bool
 }
 
 func (c *ChannelConfig) setDefaults() *ChannelConfig {
@@ -20,22 +29,52 @@ func (c ChannelConfig) validate() error {
 		return errors.New("No \"name\" defined")
 	}
 
+	for _, level := range []logman.Level{c.MinLevel, c.MaxLevel} {
+		if level != logman.NotSet &&
+			(level < logman.CriticalLevel || level > logman.DebugLevel) {
+			return fmt.Errorf("Invalid level: %d", level)
+		}
+	}
+
+	if c.MinLevel != logman.NotSet && c.MaxLevel != logman.NotSet &&
+		c.MinLevel < c.MaxLevel {
+		return fmt.Errorf(
+			"Level \"%s\" is more severe than \"%s\"", c.MinLevel, c.MaxLevel,
+		)
+	}
+
 	return nil
 }
 
+// accepts reports whether the level is within the range of the channel,
+// levels are ordered from the most severe one.
+func (c ChannelConfig) accepts(level logman.Level) bool {
+	if c.MinLevel != logman.NotSet && level > c.MinLevel {
+		return false
+	}
+
+	if c.MaxLevel != logman.NotSet && level < c.MaxLevel {
+		return false
+	}
+
+	return true
+}
+
 type LoggerConfig struct {
 	Level    logman.Level
 	Channels []ChannelConfig
+	// ErrorChannel is the channel failures of writing to the channels
+	// are reported to, stderr is used if it's not set.
+	ErrorChannel string
+	// StopOnError stops passing an entry to the rest of the channels
+	// once writing to one of them failed.
+	StopOnError bool
 }
 
 func (c LoggerConfig) DriverName() string {
 	return DriverName
 }
 func (c *LoggerConfig) setDefaults(lm *logman.Logman) *LoggerConfig {
-	if c.Level == logman.NotSet {
-		c.Level = lm.Level()
-	}
-
 	for _, chCfg := range c.Channels {
 		chCfg.setDefaults()
 	}
@@ -43,7 +82,7 @@ func (c *LoggerConfig) setDefaults(lm *logman.Logman) *LoggerConfig {
 	return c
 }
 func (c LoggerConfig) validate(lm *logman.Logman) error {
-	if c.Level < logman.CriticalLevel || c.Level > logman.DebugLevel {
+	if c.Level > logman.DebugLevel {
 		return fmt.Errorf("Invalid log level: %d", c.Level)
 	}
 
@@ -58,14 +97,6 @@ func (c LoggerConfig) validate(lm *logman.Logman) error {
 			)
 		}
 
-		for lmChName, lmChCfg := range lm.Config().Channels {
-			if chCfg.Name == lmChName && lmChCfg.DriverName() == DriverName {
-				return fmt.Errorf(
-					"Recursive usage of stack logger \"%s\"", chCfg.Name,
-				)
-			}
-		}
-
 		if _, exists := lm.Config().Channels[chCfg.Name]; !exists {
 			return fmt.Errorf(
 				"No configuration defined for channel \"%s\"", chCfg.Name,
@@ -73,6 +104,15 @@ func (c LoggerConfig) validate(lm *logman.Logman) error {
 		}
 	}
 
+	if c.ErrorChannel != "" {
+		if _, exists := lm.Config().Channels[c.ErrorChannel]; !exists {
+			return fmt.Errorf(
+				"No configuration defined for error channel \"%s\"",
+				c.ErrorChannel,
+			)
+		}
+	}
+
 	return nil
 }
 
@@ -131,6 +171,37 @@ func parseConfig(c logman.ChannelConfig) (LoggerConfig, error) {
 						continue
 					}
 
+					if opt == "minLevel" || opt == "maxLevel" {
+						level, err := logman.ParseLevelValue(v)
+						if err != nil {
+							return cfg, fmt.Errorf(
+								"Failed to parse \"%s\" "+
+									"in \"channels\" option #%d: %w",
+								opt, n+1, err,
+							)
+						}
+
+						if opt == "minLevel" {
+							chCfg.MinLevel = level
+						} else {
+							chCfg.MaxLevel = level
+						}
+						continue
+					}
+
+					if opt == "async" {
+						isAsync, ok := v.(bool)
+						if !ok {
+							return cfg, fmt.Errorf(
+								"Failed to parse \"async\" "+
+									"in \"channels\" option #%d",
+								n+1,
+							)
+						}
+						chCfg.Async = isAsync
+						continue
+					}
+
 					if opt == "name" {
 						name, ok := v.(string)
 						if !ok {
@@ -154,6 +225,28 @@ func parseConfig(c logman.ChannelConfig) (LoggerConfig, error) {
 			continue
 		}
 
+		if option == "errorChannel" {
+			errorChannel, ok := val.(string)
+			if !ok {
+				return cfg, fmt.Errorf(
+					"Failed to parse \"errorChannel\" option",
+				)
+			}
+			cfg.ErrorChannel = errorChannel
+			continue
+		}
+
+		if option == "stopOnError" {
+			stopOnError, ok := val.(bool)
+			if !ok {
+				return cfg, fmt.Errorf(
+					"Failed to parse \"stopOnError\" option",
+				)
+			}
+			cfg.StopOnError = stopOnError
+			continue
+		}
+
 		return cfg, fmt.Errorf("Unknown option passsed: %s", option)
 	}
 
@@ -1,9 +1,14 @@
 package stack
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"sync/atomic"
 
 	"github.com/Chekunin/logman"
+	"github.com/Chekunin/logman/drivers/async"
+	"go.uber.org/multierr"
 )
 
 const DriverName = "stack"
@@ -21,16 +26,45 @@ func (d driver) CreateLogger(
 
 	return newLogger(cfg, lm)
 }
+func (d driver) Dependencies(c logman.ChannelConfig) ([]string, error) {
+	cfg, err := parseConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse config: %w", err)
+	}
+
+	deps := []string{}
+	for _, chCfg := range cfg.Channels {
+		deps = append(deps, chCfg.Name)
+	}
+
+	if cfg.ErrorChannel != "" {
+		deps = append(deps, cfg.ErrorChannel)
+	}
+
+	return deps, nil
+}
+
+// FailureCounter is implemented by stack loggers, it's obtained from
+// the channel returned by Logman.Channels:
+//
+//	counter := lm.Channels("stack")["stack"].(stack.FailureCounter)
+//	failures := counter.Failures()
+type FailureCounter interface {
+	// Failures returns the number of failed writes per child channel.
+	Failures() map[string]uint64
+}
 
 type channel struct {
-	cfg    ChannelConfig
-	logger logman.Logger
+	cfg      ChannelConfig
+	logger   logman.Logger
+	failures *atomic.Uint64
 }
 
 type logger struct {
-	cfg      LoggerConfig
-	channels []channel
-	logman   *logman.Logman
+	cfg       LoggerConfig
+	level     *logman.AtomicLevel
+	channels  []channel
+	errLogger logman.Logger
 }
 
 func newLogger(cfg LoggerConfig, lm *logman.Logman) (*logger, error) {
@@ -39,8 +73,8 @@ func newLogger(cfg LoggerConfig, lm *logman.Logman) (*logger, error) {
 	}
 
 	return &logger{
-		cfg:    cfg,
-		logman: lm,
+		cfg:   cfg,
+		level: lm.NewChannelLevel(cfg.Level),
 	}, nil
 }
 
@@ -60,34 +94,243 @@ func (l *logger) Critical(msg string, fields ...logman.Fields) {
 	l.Log(logman.CriticalLevel, msg, fields...)
 }
 func (l *logger) Log(level logman.Level, msg string, fields ...logman.Fields) {
+	l.LogContext(context.Background(), level, msg, fields...)
+}
+func (l *logger) DebugContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.DebugLevel, msg, fields...)
+}
+func (l *logger) InfoContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.InfoLevel, msg, fields...)
+}
+func (l *logger) WarningContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.WarningLevel, msg, fields...)
+}
+func (l *logger) ErrorContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.ErrorLevel, msg, fields...)
+}
+func (l *logger) CriticalContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.CriticalLevel, msg, fields...)
+}
+func (l *logger) LogContext(
+	ctx context.Context,
+	level logman.Level,
+	msg string,
+	fields ...logman.Fields,
+) {
 	if l.Level() < level {
 		return
 	}
 
-	// deferred init (@TODO: add smth like `onCreated` hook to logman?)
-	if len(l.channels) == 0 {
-		for _, c := range l.cfg.Channels {
-			l.channels = append(l.channels, channel{
-				logger: l.logman.Channels(c.Name)[c.Name],
-				cfg:    c,
-			})
+	for _, c := range l.channels {
+		logged, err := c.log(ctx, level, msg, fields)
+		if err != nil {
+			l.fail(ctx, c, msg, err)
+
+			if l.cfg.StopOnError {
+				break
+			}
+			continue
+		}
+
+		if logged && c.cfg.DisableBubble {
+			break
 		}
 	}
+}
 
-	for _, c := range l.channels {
-		if c.logger.Level() < level {
-			continue
+// log writes to the channel if it accepts the level, recovering
+// from its panics.
+func (c channel) log(
+	ctx context.Context,
+	level logman.Level,
+	msg string,
+	fields []logman.Fields,
+) (logged bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logged, err = false, fmt.Errorf("Panic: %v", r)
 		}
+	}()
 
-		c.logger.Log(level, msg, fields...) // @TODO: use goroutines?
+	if c.logger.Level() < level || !c.cfg.accepts(level) {
+		return false, nil
+	}
 
-		if c.cfg.DisableBubble {
-			break
+	c.logger.LogContext(ctx, level, msg, fields...)
+
+	return true, nil
+}
+
+// fail counts and reports a failed write to the channel.
+func (l *logger) fail(
+	ctx context.Context,
+	c channel,
+	msg string,
+	err error,
+) {
+	c.failures.Add(1)
+	l.reportFailure(ctx, c, msg, err)
+}
+
+func (l *logger) reportFailure(
+	ctx context.Context,
+	c channel,
+	msg string,
+	err error,
+) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(
+				os.Stderr,
+				"stack: failed to report failure of channel %s: %v\n",
+				c.cfg.Name, r,
+			)
 		}
+	}()
+
+	if l.errLogger == nil {
+		fmt.Fprintf(
+			os.Stderr,
+			"stack: failed to write to channel %s: %v\n",
+			c.cfg.Name, err,
+		)
+		return
 	}
+
+	l.errLogger.ErrorContext(
+		ctx,
+		"Failed to write to channel",
+		logman.Fields{
+			"channel":     c.cfg.Name,
+			"error":       err.Error(),
+			"originalMsg": msg,
+		},
+	)
 }
 func (l *logger) Level() logman.Level {
-	return l.cfg.Level
+	return l.level.Level()
+}
+func (l *logger) SetLevel(level logman.Level) {
+	l.level.SetLevel(level)
+}
+func (l *logger) OwnLevel() logman.Level {
+	return l.level.OwnLevel()
+}
+func (l *logger) With(fields logman.Fields) logman.Logger {
+	child := &logger{
+		cfg:       l.cfg,
+		level:     l.level,
+		errLogger: l.errLogger,
+	}
+	for _, c := range l.channels {
+		child.channels = append(child.channels, channel{
+			logger:   c.logger.With(fields),
+			cfg:      c.cfg,
+			failures: c.failures,
+		})
+	}
+
+	return child
+}
+
+// Sync flushes the child channels.
+func (l *logger) Sync() error {
+	var err error
+	for _, c := range l.channels {
+		if s, ok := c.logger.(logman.Syncer); ok {
+			if syncErr := s.Sync(); syncErr != nil {
+				err = multierr.Append(err, fmt.Errorf(
+					"Failed to sync channel %s: %w", c.cfg.Name, syncErr,
+				))
+			}
+		}
+	}
+
+	return err
+}
+
+// Failures returns the number of failed writes per channel,
+// see FailureCounter.
+func (l *logger) Failures() map[string]uint64 {
+	failures := map[string]uint64{}
+	for _, c := range l.channels {
+		failures[c.cfg.Name] += c.failures.Load()
+	}
+
+	return failures
+}
+
+// Close flushes and stops the async wrappers of the child channels.
+// The channels themselves aren't closed by the stack logger as they're
+// channels of their own, closed by logman.
+func (l *logger) Close() error {
+	var err error
+	for _, c := range l.channels {
+		if w, ok := c.logger.(*async.Logger); ok && c.cfg.Async {
+			err = multierr.Append(err, w.Close())
+		}
+	}
+
+	return err
+}
+
+// Init resolves the child channels once logman created them.
+func (l *logger) Init(lm *logman.Logman) error {
+	for _, c := range l.cfg.Channels {
+		ch := channel{
+			logger:   lm.Channels(c.Name)[c.Name],
+			cfg:      c,
+			failures: &atomic.Uint64{},
+		}
+
+		if c.Async {
+			// panics of the async writes are counted by the workers
+			w, err := async.Wrap(ch.logger, async.Options{
+				OnFailure: func(
+					ctx context.Context,
+					_ logman.Level,
+					msg string,
+					err error,
+				) {
+					l.fail(ctx, ch, msg, err)
+				},
+			})
+			if err != nil {
+				return fmt.Errorf(
+					"Failed to wrap channel \"%s\": %w", c.Name, err,
+				)
+			}
+			ch.logger = w
+		}
+
+		l.channels = append(l.channels, ch)
+	}
+
+	if l.cfg.ErrorChannel != "" {
+		l.errLogger = lm.Channels(l.cfg.ErrorChannel)[l.cfg.ErrorChannel]
+	}
+
+	return nil
 }
 
 func init() {
@@ -0,0 +1,221 @@
+package syslog
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Chekunin/logman"
+)
+
+const (
+	RFC5424 = "rfc5424"
+	RFC3164 = "rfc3164"
+)
+
+const (
+	// OctetCounting frames messages sent over streams with their length
+	// (RFC 6587).
+	OctetCounting = "octetCounting"
+	// NonTransparent frames messages sent over streams with a newline.
+	NonTransparent = "nonTransparent"
+)
+
+var facilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+type LoggerConfig struct {
+	Level logman.Level
+	// Network is one of "udp", "tcp", "tls", "unix", "unixgram".
+	// The local syslog socket is used if it's empty.
+	Network string
+	Address string
+	// Format is RFC5424 (default) or RFC3164.
+	Format   string
+	Facility string
+	AppName  string
+	Hostname string
+	// StructuredDataID is the SD-ID fields are sent with in RFC 5424,
+	// e.g. "app@<private enterprise number>". The fields are appended
+	// to the message if it's not set.
+	StructuredDataID string
+	// Framing of messages sent over TCP and TLS, OctetCounting
+	// by default, and over stream Unix sockets, NonTransparent
+	// by default.
+	Framing   string
+	TLSConfig *tls.Config
+	// CAFile is a PEM file with certificates added to the root CAs
+	// of TLSConfig, it's read when the logger is created.
+	CAFile string
+}
+
+func (c LoggerConfig) DriverName() string {
+	return DriverName
+}
+func (c *LoggerConfig) setDefaults(lm *logman.Logman) *LoggerConfig {
+	if c.Format == "" {
+		c.Format = RFC5424
+	}
+
+	if c.Facility == "" {
+		c.Facility = "user"
+	}
+
+	if c.AppName == "" {
+		c.AppName = filepath.Base(os.Args[0])
+	}
+
+	if c.Hostname == "" {
+		c.Hostname, _ = os.Hostname()
+	}
+
+	return c
+}
+func (c LoggerConfig) validate(_ *logman.Logman) error {
+	if c.Level > logman.DebugLevel {
+		return fmt.Errorf("Invalid log level: %d", c.Level)
+	}
+
+	switch c.Network {
+	case "":
+		if c.Address != "" {
+			return errors.New("No \"network\" defined for \"address\"")
+		}
+	case "udp", "tcp", "tls", "unix", "unixgram":
+		if c.Address == "" {
+			return errors.New("No \"address\" defined")
+		}
+	default:
+		return fmt.Errorf("Invalid network: %s", c.Network)
+	}
+
+	if c.Format != RFC5424 && c.Format != RFC3164 {
+		return fmt.Errorf("Invalid format: %s", c.Format)
+	}
+
+	if _, exists := facilities[c.Facility]; !exists {
+		return fmt.Errorf("Invalid facility: %s", c.Facility)
+	}
+
+	if c.Framing != "" &&
+		c.Framing != OctetCounting && c.Framing != NonTransparent {
+		return fmt.Errorf("Invalid framing: %s", c.Framing)
+	}
+
+	if c.StructuredDataID != "" &&
+		(paramName(c.StructuredDataID) != c.StructuredDataID ||
+			!strings.Contains(c.StructuredDataID, "@")) {
+		return fmt.Errorf(
+			"Invalid structured data ID: %s", c.StructuredDataID,
+		)
+	}
+
+	return nil
+}
+
+// loadCAFile adds the certificates of CAFile to a copy of TLSConfig.
+func (c *LoggerConfig) loadCAFile() error {
+	if c.CAFile == "" {
+		return nil
+	}
+
+	pem, err := os.ReadFile(c.CAFile)
+	if err != nil {
+		return fmt.Errorf("Failed to read CA file: %w", err)
+	}
+
+	tlsConfig := &tls.Config{}
+	if c.TLSConfig != nil {
+		tlsConfig = c.TLSConfig.Clone()
+	}
+
+	if tlsConfig.RootCAs == nil {
+		tlsConfig.RootCAs = x509.NewCertPool()
+	}
+	if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+		return errors.New("No certificates found in CA file")
+	}
+	c.TLSConfig = tlsConfig
+
+	return nil
+}
+
+func parseConfig(c logman.ChannelConfig) (LoggerConfig, error) {
+	if cfg, ok := c.(LoggerConfig); ok {
+		return cfg, nil
+	}
+
+	cfg := LoggerConfig{}
+
+	rawCfg, ok := c.(logman.ChannelArbitraryConfig)
+	if !ok {
+		return cfg, errors.New("Invalid config structure")
+	}
+
+	cfg.Level = rawCfg.Level
+
+	insecureSkipVerify := false
+
+	for option, val := range rawCfg.Extra {
+		var ok bool
+
+		switch option {
+		case "network":
+			cfg.Network, ok = val.(string)
+		case "address":
+			cfg.Address, ok = val.(string)
+		case "format":
+			cfg.Format, ok = val.(string)
+		case "facility":
+			cfg.Facility, ok = val.(string)
+		case "appName":
+			cfg.AppName, ok = val.(string)
+		case "hostname":
+			cfg.Hostname, ok = val.(string)
+		case "structuredDataId":
+			cfg.StructuredDataID, ok = val.(string)
+		case "framing":
+			cfg.Framing, ok = val.(string)
+		case "caFile":
+			cfg.CAFile, ok = val.(string)
+		case "insecureSkipVerify":
+			insecureSkipVerify, ok = val.(bool)
+		default:
+			return cfg, fmt.Errorf("Unknown option passsed: %s", option)
+		}
+
+		if !ok {
+			return cfg, fmt.Errorf("Failed to parse \"%s\" option", option)
+		}
+	}
+
+	if insecureSkipVerify {
+		cfg.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,196 @@
+package syslog
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var localSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+const (
+	dialTimeout  = 5 * time.Second
+	writeTimeout = 5 * time.Second
+	minBackoff   = 100 * time.Millisecond
+	maxBackoff   = 30 * time.Second
+)
+
+var notConnectedErr = errors.New("Not connected to syslog")
+
+// conn is a connection to syslog. Once a write fails it's redialed
+// in background, messages written meanwhile are dropped.
+type conn struct {
+	cfg     LoggerConfig
+	dropped atomic.Uint64
+
+	mu sync.Mutex
+	nc net.Conn
+	// framing of messages sent over nc, empty for datagrams
+	framing      string
+	reconnecting bool
+	closed       bool
+	stop         chan struct{}
+}
+
+func newConn(cfg LoggerConfig) (*conn, error) {
+	nc, network, err := dial(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conn{
+		cfg:     cfg,
+		nc:      nc,
+		framing: framing(cfg, network),
+		stop:    make(chan struct{}),
+	}, nil
+}
+
+// dial connects to syslog and returns the network it connected with.
+func dial(cfg LoggerConfig) (net.Conn, string, error) {
+	switch cfg.Network {
+	case "":
+		for _, path := range localSockets {
+			for _, network := range []string{"unixgram", "unix"} {
+				nc, err := net.DialTimeout(network, path, dialTimeout)
+				if err == nil {
+					return nc, network, nil
+				}
+			}
+		}
+		return nil, "", errors.New("No local syslog socket found")
+	case "tls":
+		nc, err := tls.DialWithDialer(
+			&net.Dialer{Timeout: dialTimeout},
+			"tcp",
+			cfg.Address,
+			cfg.TLSConfig,
+		)
+		if err != nil {
+			return nil, "", fmt.Errorf("Failed to connect to syslog: %w", err)
+		}
+		return nc, "tcp", nil
+	default:
+		nc, err := net.DialTimeout(cfg.Network, cfg.Address, dialTimeout)
+		if err != nil {
+			return nil, "", fmt.Errorf("Failed to connect to syslog: %w", err)
+		}
+		return nc, cfg.Network, nil
+	}
+}
+
+// framing returns the framing of messages sent over the network.
+// Local syslog daemons expect newlines on stream sockets, so the unix
+// network is framed with them unless the framing is set explicitly.
+func framing(cfg LoggerConfig, network string) string {
+	switch network {
+	case "tcp":
+		if cfg.Framing == "" {
+			return OctetCounting
+		}
+		return cfg.Framing
+	case "unix":
+		if cfg.Framing == "" {
+			return NonTransparent
+		}
+		return cfg.Framing
+	}
+
+	return ""
+}
+
+// write sends the message, it's dropped if the connection is down.
+func (c *conn) write(msg string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.nc == nil {
+		c.dropped.Add(1)
+		return notConnectedErr
+	}
+
+	switch c.framing {
+	case OctetCounting:
+		msg = strconv.Itoa(len(msg)) + " " + msg
+	case NonTransparent:
+		msg += "\n"
+	}
+
+	_ = c.nc.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := c.nc.Write([]byte(msg)); err != nil {
+		c.dropped.Add(1)
+		c.nc.Close()
+		c.nc = nil
+		c.startReconnect()
+
+		return err
+	}
+
+	return nil
+}
+
+// startReconnect starts redialing in background, c.mu must be held.
+func (c *conn) startReconnect() {
+	if c.reconnecting || c.closed {
+		return
+	}
+	c.reconnecting = true
+
+	go c.reconnect()
+}
+
+func (c *conn) reconnect() {
+	backoff := minBackoff
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-time.After(backoff):
+		}
+
+		nc, network, err := dial(c.cfg)
+		if err != nil {
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+
+		c.mu.Lock()
+		c.reconnecting = false
+		if c.closed {
+			c.mu.Unlock()
+			nc.Close()
+			return
+		}
+		c.nc, c.framing = nc, framing(c.cfg, network)
+		c.mu.Unlock()
+
+		return
+	}
+}
+
+func (c *conn) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	close(c.stop)
+
+	if c.nc == nil {
+		return nil
+	}
+
+	err := c.nc.Close()
+	c.nc = nil
+
+	return err
+}
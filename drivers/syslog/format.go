@@ -0,0 +1,190 @@
+package syslog
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Chekunin/logman"
+)
+
+// severities maps levels to syslog severities.
+var severities = map[logman.Level]int{
+	logman.CriticalLevel: 2,
+	logman.ErrorLevel:    3,
+	logman.WarningLevel:  4,
+	logman.InfoLevel:     6,
+	logman.DebugLevel:    7,
+}
+
+type formatter struct {
+	cfg      LoggerConfig
+	facility int
+	pid      int
+}
+
+func newFormatter(cfg LoggerConfig) formatter {
+	return formatter{
+		cfg:      cfg,
+		facility: facilities[cfg.Facility],
+		pid:      os.Getpid(),
+	}
+}
+
+func (f formatter) format(
+	ts time.Time,
+	level logman.Level,
+	msg string,
+	fields map[string]interface{},
+) string {
+	pri := f.facility*8 + severities[level]
+
+	if f.cfg.Format == RFC3164 {
+		return f.formatRFC3164(pri, ts, msg, fields)
+	}
+
+	return f.formatRFC5424(pri, ts, msg, fields)
+}
+
+// formatRFC5424 formats an entry like
+// <14>1 2006-01-02T15:04:05.000000Z host app 42 - [app@12345 k="v"] msg,
+// the fields are appended to the message like in RFC 3164 if there's
+// no StructuredDataID.
+func (f formatter) formatRFC5424(
+	pri int,
+	ts time.Time,
+	msg string,
+	fields map[string]interface{},
+) string {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(
+		b,
+		"<%d>1 %s %s %s %d - ",
+		pri,
+		ts.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		headerValue(f.cfg.Hostname, 255),
+		headerValue(f.cfg.AppName, 48),
+		f.pid,
+	)
+
+	if len(fields) == 0 || f.cfg.StructuredDataID == "" {
+		b.WriteString("-")
+	} else {
+		b.WriteString("[")
+		b.WriteString(f.cfg.StructuredDataID)
+		for _, k := range sortedKeys(fields) {
+			b.WriteString(" ")
+			b.WriteString(paramName(k))
+			b.WriteString(`="`)
+			b.WriteString(paramValue(fieldValue(fields[k])))
+			b.WriteString(`"`)
+		}
+		b.WriteString("]")
+	}
+
+	if msg != "" {
+		b.WriteString(" ")
+		b.WriteString(msg)
+	}
+
+	if f.cfg.StructuredDataID == "" {
+		writeFields(b, fields)
+	}
+
+	return b.String()
+}
+
+// formatRFC3164 formats an entry like
+// <14>Jan  2 15:04:05 host app[42]: msg k=v.
+func (f formatter) formatRFC3164(
+	pri int,
+	ts time.Time,
+	msg string,
+	fields map[string]interface{},
+) string {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(
+		b,
+		"<%d>%s %s %s[%d]: %s",
+		pri,
+		ts.Format(time.Stamp),
+		headerValue(f.cfg.Hostname, 255),
+		headerValue(f.cfg.AppName, 32),
+		f.pid,
+		msg,
+	)
+
+	writeFields(b, fields)
+
+	return b.String()
+}
+
+func writeFields(b *strings.Builder, fields map[string]interface{}) {
+	for _, k := range sortedKeys(fields) {
+		fmt.Fprintf(b, " %s=%q", k, fieldValue(fields[k]))
+	}
+}
+
+func fieldValue(v interface{}) string {
+	if err, ok := v.(error); ok {
+		return err.Error()
+	}
+
+	return fmt.Sprint(v)
+}
+
+// headerValue replaces the characters not allowed in the header fields
+// and truncates the value.
+func headerValue(s string, maxLen int) string {
+	if s == "" {
+		return "-"
+	}
+
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, s)
+
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+
+	return s
+}
+
+// paramName replaces the characters not allowed in SD-NAME.
+func paramName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, s)
+
+	if len(s) > 32 {
+		s = s[:32]
+	}
+
+	return s
+}
+
+// paramValue escapes '"', '\' and ']' in PARAM-VALUE.
+func paramValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(s)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,169 @@
+package syslog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Chekunin/logman"
+)
+
+const DriverName = "syslog"
+
+type driver struct{}
+
+func (d driver) CreateLogger(
+	lm *logman.Logman,
+	c logman.ChannelConfig,
+) (logman.Logger, error) {
+	cfg, err := parseConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse config: %w", err)
+	}
+
+	return newLogger(cfg, lm)
+}
+
+type logger struct {
+	cfg       LoggerConfig
+	level     *logman.AtomicLevel
+	formatter formatter
+	conn      *conn
+	fields    []logman.Fields
+}
+
+func newLogger(cfg LoggerConfig, lm *logman.Logman) (*logger, error) {
+	if err := cfg.setDefaults(lm).validate(lm); err != nil {
+		return nil, fmt.Errorf("Invalid config: %w", err)
+	}
+
+	if err := cfg.loadCAFile(); err != nil {
+		return nil, err
+	}
+
+	c, err := newConn(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logger{
+		cfg:       cfg,
+		level:     lm.NewChannelLevel(cfg.Level),
+		formatter: newFormatter(cfg),
+		conn:      c,
+	}, nil
+}
+
+func (l *logger) Debug(msg string, fields ...logman.Fields) {
+	l.Log(logman.DebugLevel, msg, fields...)
+}
+func (l *logger) Info(msg string, fields ...logman.Fields) {
+	l.Log(logman.InfoLevel, msg, fields...)
+}
+func (l *logger) Warning(msg string, fields ...logman.Fields) {
+	l.Log(logman.WarningLevel, msg, fields...)
+}
+func (l *logger) Error(msg string, fields ...logman.Fields) {
+	l.Log(logman.ErrorLevel, msg, fields...)
+}
+func (l *logger) Critical(msg string, fields ...logman.Fields) {
+	l.Log(logman.CriticalLevel, msg, fields...)
+}
+func (l *logger) Log(level logman.Level, msg string, fields ...logman.Fields) {
+	l.LogContext(context.Background(), level, msg, fields...)
+}
+func (l *logger) DebugContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.DebugLevel, msg, fields...)
+}
+func (l *logger) InfoContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.InfoLevel, msg, fields...)
+}
+func (l *logger) WarningContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.WarningLevel, msg, fields...)
+}
+func (l *logger) ErrorContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.ErrorLevel, msg, fields...)
+}
+func (l *logger) CriticalContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.CriticalLevel, msg, fields...)
+}
+func (l *logger) LogContext(
+	ctx context.Context,
+	level logman.Level,
+	msg string,
+	fields ...logman.Fields,
+) {
+	if l.Level() < level {
+		return
+	}
+
+	var recFields []logman.Fields
+	if rec, exists := logman.RecordFromContext(ctx); exists {
+		recFields = append(recFields, rec.Fields())
+	}
+
+	// later fields override earlier ones
+	merged := map[string]interface{}{}
+	for _, fieldSets := range [][]logman.Fields{
+		recFields,
+		l.fields,
+		logman.FieldsFromContext(ctx),
+		fields,
+	} {
+		for _, fieldSet := range fieldSets {
+			for k, v := range logman.ExpandErrors(fieldSet) {
+				merged[k] = v
+			}
+		}
+	}
+
+	_ = l.conn.write(l.formatter.format(time.Now(), level, msg, merged))
+}
+func (l *logger) Level() logman.Level {
+	return l.level.Level()
+}
+func (l *logger) SetLevel(level logman.Level) {
+	l.level.SetLevel(level)
+}
+func (l *logger) OwnLevel() logman.Level {
+	return l.level.OwnLevel()
+}
+func (l *logger) With(fields logman.Fields) logman.Logger {
+	child := *l
+	child.fields = append(append([]logman.Fields{}, l.fields...), fields)
+
+	return &child
+}
+
+// Dropped returns the number of messages dropped while disconnected,
+// it's available through interface{ Dropped() uint64 }.
+func (l *logger) Dropped() uint64 {
+	return l.conn.dropped.Load()
+}
+func (l *logger) Close() error {
+	return l.conn.close()
+}
+
+func init() {
+	logman.RegisterDriver(DriverName, driver{})
+}
@@ -0,0 +1,213 @@
+package syslog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Chekunin/logman"
+)
+
+func TestUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	l, err := newLogger(LoggerConfig{
+		Level:            logman.DebugLevel,
+		Network:          "udp",
+		Address:          pc.LocalAddr().String(),
+		Facility:         "local0",
+		AppName:          "app",
+		Hostname:         "host",
+		StructuredDataID: "app@12345",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	l.Warning("disk is full", logman.Fields{"path": `/var/"log"`})
+
+	buf := make([]byte, 2048)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := regexp.MustCompile(
+		`^<132>1 \S+Z host app \d+ - ` +
+			`\[app@12345 path="/var/\\"log\\""\] disk is full$`,
+	)
+	if msg := string(buf[:n]); !expected.MatchString(msg) {
+		t.Errorf("Unexpected message: %q", msg)
+	}
+}
+
+func TestTCPFraming(t *testing.T) {
+	tests := []struct {
+		framing  string
+		readMsgs func(r *bufio.Reader) ([]string, error)
+	}{
+		{
+			framing: OctetCounting,
+			readMsgs: func(r *bufio.Reader) ([]string, error) {
+				msgs := []string{}
+				for i := 0; i < 2; i++ {
+					rawLen, err := r.ReadString(' ')
+					if err != nil {
+						return nil, err
+					}
+
+					n, err := strconv.Atoi(strings.TrimSuffix(rawLen, " "))
+					if err != nil {
+						return nil, err
+					}
+
+					msg := make([]byte, n)
+					if _, err := io.ReadFull(r, msg); err != nil {
+						return nil, err
+					}
+					msgs = append(msgs, string(msg))
+				}
+				return msgs, nil
+			},
+		},
+		{
+			framing: NonTransparent,
+			readMsgs: func(r *bufio.Reader) ([]string, error) {
+				msgs := []string{}
+				for i := 0; i < 2; i++ {
+					msg, err := r.ReadString('\n')
+					if err != nil {
+						return nil, err
+					}
+					msgs = append(msgs, strings.TrimSuffix(msg, "\n"))
+				}
+				return msgs, nil
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.framing, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ln.Close()
+
+			l, err := newLogger(LoggerConfig{
+				Level:    logman.DebugLevel,
+				Network:  "tcp",
+				Address:  ln.Addr().String(),
+				Format:   RFC3164,
+				AppName:  "app",
+				Hostname: "host",
+				Framing:  test.framing,
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer l.Close()
+
+			c, err := ln.Accept()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+
+			l.Info("first")
+			l.Error("second", logman.Fields{"n": 2})
+
+			_ = c.SetReadDeadline(time.Now().Add(5 * time.Second))
+			msgs, err := test.readMsgs(bufio.NewReader(c))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := []*regexp.Regexp{
+				regexp.MustCompile(`^<14>\w{3} [ \d]\d \S+ host app\[\d+\]: first$`),
+				regexp.MustCompile(`^<11>\w{3} [ \d]\d \S+ host app\[\d+\]: second n="2"$`),
+			}
+			for n, msg := range msgs {
+				if !expected[n].MatchString(msg) {
+					t.Errorf("Unexpected message #%d: %q", n+1, msg)
+				}
+			}
+		})
+	}
+}
+
+func TestFieldsWithoutStructuredDataID(t *testing.T) {
+	f := newFormatter(LoggerConfig{
+		Format:   RFC5424,
+		Facility: "user",
+		AppName:  "app",
+		Hostname: "host",
+	})
+
+	msg := f.format(
+		time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		logman.InfoLevel,
+		"started",
+		map[string]interface{}{"port": 80},
+	)
+
+	expected := regexp.MustCompile(
+		`^<14>1 2006-01-02T15:04:05.000000Z host app \d+ - - started port="80"$`,
+	)
+	if !expected.MatchString(msg) {
+		t.Errorf("Unexpected message: %q", msg)
+	}
+}
+
+func TestUnixStreamFraming(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	l, err := newLogger(LoggerConfig{
+		Level:    logman.DebugLevel,
+		Network:  "unix",
+		Address:  path,
+		Format:   RFC3164,
+		AppName:  "app",
+		Hostname: "host",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	c, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	l.Info("started")
+
+	_ = c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	msg, err := bufio.NewReader(c).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := regexp.MustCompile(`^<14>\w{3} [ \d]\d \S+ host app\[\d+\]: started\n$`)
+	if !expected.MatchString(msg) {
+		t.Errorf("Unexpected message: %q", msg)
+	}
+}
@@ -8,6 +8,9 @@ import (
 )
 
 type LoggerConfig struct {
+	// EnableStackTrace and EnableCaller capture the stack trace of errors
+	// and the caller for the channel when logman doesn't, see
+	// logman.Config.EnableCaller.
 	EnableStackTrace bool
 	EnableCaller     bool
 	Encoding         string
@@ -23,10 +26,6 @@ func (c *LoggerConfig) setDefaults(lm *logman.Logman) *LoggerConfig {
 		c.Encoding = "json"
 	}
 
-	if c.Level == logman.NotSet {
-		c.Level = lm.Level()
-	}
-
 	if len(c.Output) == 0 {
 		c.Output = []string{"stderr"}
 	}
@@ -34,7 +33,7 @@ func (c *LoggerConfig) setDefaults(lm *logman.Logman) *LoggerConfig {
 	return c
 }
 func (c LoggerConfig) validate(_ *logman.Logman) error {
-	if c.Level < logman.CriticalLevel || c.Level > logman.DebugLevel {
+	if c.Level > logman.DebugLevel {
 		return fmt.Errorf("Invalid log level: %d", c.Level)
 	}
 
@@ -81,6 +80,11 @@ func parseConfig(c logman.ChannelConfig) (LoggerConfig, error) {
 		}
 
 		if option == "output" {
+			if output, ok := val.(string); ok {
+				cfg.Output = append(cfg.Output, output)
+				continue
+			}
+
 			rawOutputs, ok := val.([]interface{})
 			if !ok {
 				return cfg, fmt.Errorf(
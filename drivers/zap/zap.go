@@ -1,9 +1,16 @@
 package zap
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"runtime"
+	"syscall"
 
 	"github.com/Chekunin/logman"
+	"github.com/Chekunin/logman/drivers/file"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -12,6 +19,10 @@ const DriverName = "zap"
 
 const zapCriticalLevel = 99
 
+// RotatingSinkScheme is the output scheme of rotating files,
+// see file.NewWriterFromURL for the options.
+const RotatingSinkScheme = "rotate"
+
 type driver struct{}
 
 func (d driver) CreateLogger(
@@ -27,8 +38,10 @@ func (d driver) CreateLogger(
 }
 
 type logger struct {
-	cfg    LoggerConfig
-	logger *zap.Logger
+	cfg         LoggerConfig
+	level       *logman.AtomicLevel
+	logger      *zap.Logger
+	closeOutput func()
 }
 
 func newLogger(cfg LoggerConfig, lm *logman.Logman) (*logger, error) {
@@ -36,38 +49,62 @@ func newLogger(cfg LoggerConfig, lm *logman.Logman) (*logger, error) {
 		return nil, fmt.Errorf("Invalid config: %w", err)
 	}
 
-	zapLogger, err := zap.Config{
-		Level:             zap.NewAtomicLevelAt(toZapLevel(cfg.Level)),
-		Development:       false,
-		Sampling:          nil,
-		DisableStacktrace: !cfg.EnableStackTrace,
-		Encoding:          cfg.Encoding,
-		EncoderConfig: zapcore.EncoderConfig{
-			TimeKey:        "ts",
-			LevelKey:       "level",
-			NameKey:        "logger",
-			CallerKey:      "caller",
-			FunctionKey:    zapcore.OmitKey,
-			MessageKey:     "msg",
-			StacktraceKey:  "stacktrace",
-			LineEnding:     zapcore.DefaultLineEnding,
-			EncodeLevel:    zapCriticalAwareLowercaseLevelEncoder,
-			EncodeTime:     zapcore.ISO8601TimeEncoder,
-			EncodeDuration: zapcore.SecondsDurationEncoder,
-			EncodeCaller:   zapcore.ShortCallerEncoder,
-		},
-		DisableCaller:    !cfg.EnableCaller,
-		OutputPaths:      cfg.Output,
-		ErrorOutputPaths: []string{"stderr"},
-	}.Build()
+	level := lm.NewChannelLevel(cfg.Level)
+
+	// zap checks the channel level on its own, following its changes
+	zapLevel := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		current := level.Level()
+
+		return current != logman.NotSet && l >= toZapLevel(current)
+	})
+
+	encCfg := zapcore.EncoderConfig{
+		TimeKey:        "ts",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		FunctionKey:    zapcore.OmitKey,
+		MessageKey:     "msg",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    logmanLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+
+	var encoder zapcore.Encoder
+	if cfg.Encoding == "console" {
+		encoder = zapcore.NewConsoleEncoder(encCfg)
+	} else {
+		encoder = zapcore.NewJSONEncoder(encCfg)
+	}
 
+	// outputs are opened here rather than by zap.Config.Build
+	// to be able to close them
+	sink, closeOutput, err := zap.Open(cfg.Output...)
 	if err != nil {
 		return nil, err
 	}
 
+	errSink, _, err := zap.Open("stderr")
+	if err != nil {
+		closeOutput()
+		return nil, err
+	}
+
+	// the caller and the stack trace are taken from logman.Record
+	// as zap would report frames of logman
+	zapLogger := zap.New(
+		zapcore.NewCore(encoder, sink, zapLevel),
+		zap.ErrorOutput(errSink),
+	)
+
 	return &logger{
-		cfg:    cfg,
-		logger: zapLogger,
+		cfg:         cfg,
+		level:       level,
+		logger:      zapLogger,
+		closeOutput: closeOutput,
 	}, nil
 }
 
@@ -87,23 +124,64 @@ func (l *logger) Critical(msg string, fields ...logman.Fields) {
 	l.Log(logman.CriticalLevel, msg, fields...)
 }
 func (l *logger) Log(level logman.Level, msg string, fields ...logman.Fields) {
+	l.LogContext(context.Background(), level, msg, fields...)
+}
+func (l *logger) DebugContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.DebugLevel, msg, fields...)
+}
+func (l *logger) InfoContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.InfoLevel, msg, fields...)
+}
+func (l *logger) WarningContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.WarningLevel, msg, fields...)
+}
+func (l *logger) ErrorContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.ErrorLevel, msg, fields...)
+}
+func (l *logger) CriticalContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.CriticalLevel, msg, fields...)
+}
+func (l *logger) LogContext(
+	ctx context.Context,
+	level logman.Level,
+	msg string,
+	fields ...logman.Fields,
+) {
 	if l.Level() < level {
 		return
 	}
 
+	if ctxFields := logman.FieldsFromContext(ctx); len(ctxFields) > 0 {
+		fields = append(append([]logman.Fields{}, ctxFields...), fields...)
+	}
+
 	switch level {
-	case logman.DebugLevel:
-		l.logger.Debug(msg, toZapFields(fields)...)
-	case logman.InfoLevel:
-		l.logger.Info(msg, toZapFields(fields)...)
-	case logman.WarningLevel:
-		l.logger.Warn(msg, toZapFields(fields)...)
-	case logman.ErrorLevel:
-		l.logger.Error(msg, toZapFields(fields)...)
-	case logman.CriticalLevel:
-		if e := l.logger.Check(zapcore.Level(zapCriticalLevel), msg); e != nil {
-			e.Write(toZapFields(fields)...)
-		}
+	case logman.DebugLevel,
+		logman.InfoLevel,
+		logman.WarningLevel,
+		logman.ErrorLevel,
+		logman.CriticalLevel:
+		l.write(ctx, level, msg, fields)
 	default:
 		l.logger.Error(
 			"Unknown log level",
@@ -115,14 +193,89 @@ func (l *logger) Log(level logman.Level, msg string, fields ...logman.Fields) {
 		)
 	}
 }
+
+// write logs the entry with the caller and the stack trace of the record
+// captured by logman, they're captured here only if the channel enables
+// them and logman didn't.
+func (l *logger) write(
+	ctx context.Context,
+	level logman.Level,
+	msg string,
+	fields []logman.Fields,
+) {
+	ce := l.logger.Check(toZapLevel(level), msg)
+	if ce == nil {
+		return
+	}
+
+	rec, exists := logman.RecordFromContext(ctx)
+	if !exists {
+		withStack := l.cfg.EnableStackTrace && level <= logman.ErrorLevel
+		if l.cfg.EnableCaller || withStack {
+			rec = logman.CaptureRecord(0, withStack)
+		}
+		if !l.cfg.EnableCaller {
+			rec.Caller = runtime.Frame{}
+		}
+	}
+
+	if rec.HasCaller() {
+		ce.Entry.Caller = zapcore.NewEntryCaller(
+			rec.Caller.PC, rec.Caller.File, rec.Caller.Line, true,
+		)
+		ce.Entry.Caller.Function = rec.Caller.Function
+	}
+	if rec.Stack != "" {
+		ce.Entry.Stack = rec.Stack
+	}
+
+	ce.Write(toZapFields(fields)...)
+}
 func (l *logger) Level() logman.Level {
-	return l.cfg.Level
+	return l.level.Level()
+}
+func (l *logger) SetLevel(level logman.Level) {
+	l.level.SetLevel(level)
+}
+func (l *logger) OwnLevel() logman.Level {
+	return l.level.OwnLevel()
+}
+func (l *logger) With(fields logman.Fields) logman.Logger {
+	return &logger{
+		cfg:         l.cfg,
+		level:       l.level,
+		logger:      l.logger.With(toZapFields([]logman.Fields{fields})...),
+		closeOutput: l.closeOutput,
+	}
+}
+
+// Sync flushes buffered entries. Errors of syncing stdout and stderr,
+// which don't support it on most platforms, are ignored.
+func (l *logger) Sync() error {
+	var err error
+	for _, syncErr := range multierr.Errors(l.logger.Sync()) {
+		if errors.Is(syncErr, syscall.EINVAL) ||
+			errors.Is(syncErr, syscall.ENOTTY) {
+			continue
+		}
+		err = multierr.Append(err, syncErr)
+	}
+
+	return err
+}
+
+// Close syncs the logger and closes its outputs.
+func (l *logger) Close() error {
+	err := l.Sync()
+	l.closeOutput()
+
+	return err
 }
 
 func toZapFields(fields []logman.Fields) []zap.Field {
 	zapFields := []zap.Field{}
 	for _, fieldSet := range fields {
-		for field, val := range fieldSet {
+		for field, val := range logman.ExpandErrors(fieldSet) {
 			zapFields = append(zapFields, zap.Any(field, val))
 		}
 	}
@@ -147,17 +300,50 @@ func toZapLevel(l logman.Level) zapcore.Level {
 	panic(fmt.Sprintf("Unknown log level: %d", l))
 }
 
-func zapCriticalAwareLowercaseLevelEncoder(
+func fromZapLevel(l zapcore.Level) logman.Level {
+	switch l {
+	case zapcore.DebugLevel:
+		return logman.DebugLevel
+	case zapcore.InfoLevel:
+		return logman.InfoLevel
+	case zapcore.WarnLevel:
+		return logman.WarningLevel
+	case zapcore.ErrorLevel:
+		return logman.ErrorLevel
+	case zapCriticalLevel:
+		return logman.CriticalLevel
+	}
+
+	return logman.NotSet
+}
+
+// logmanLevelEncoder encodes levels with the logman level names.
+func logmanLevelEncoder(
 	l zapcore.Level,
 	enc zapcore.PrimitiveArrayEncoder,
 ) {
-	if l == zapCriticalLevel {
-		enc.AppendString("critical")
+	if level := fromZapLevel(l); level != logman.NotSet {
+		enc.AppendString(level.String())
 	} else {
 		enc.AppendString(l.String())
 	}
 }
 
+// newRotatingSink creates a file.Writer for outputs like
+// rotate:///var/log/app.log?maxSize=104857600&maxBackups=7.
+func newRotatingSink(u *url.URL) (zap.Sink, error) {
+	w, err := file.NewWriterFromURL(u)
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
 func init() {
 	logman.RegisterDriver(DriverName, driver{})
+
+	if err := zap.RegisterSink(RotatingSinkScheme, newRotatingSink); err != nil {
+		panic(err)
+	}
 }
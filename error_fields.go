@@ -0,0 +1,144 @@
+package logman
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Err returns fields holding the error under the "error" key.
+func Err(err error) Fields {
+	return Fields{"error": err}
+}
+
+// ErrorFields renders the error as fields: key holds the message,
+// keyVerbose the "%+v" representation if it differs, keyChain the messages
+// of the wrapped errors (both errors.Unwrap and errors.Join ones)
+// and keyStack the stack trace of the innermost error carrying one,
+// e.g. created by github.com/pkg/errors.
+func ErrorFields(key string, err error) Fields {
+	if err == nil {
+		return Fields{key: nil}
+	}
+
+	fields := Fields{key: err.Error()}
+
+	if _, ok := err.(fmt.Formatter); ok {
+		if verbose := fmt.Sprintf("%+v", err); verbose != err.Error() {
+			fields[key+"Verbose"] = verbose
+		}
+	}
+
+	var chain []string
+	for _, wrapped := range unwrapAll(err) {
+		chain = append(chain, wrapped.Error())
+	}
+	if len(chain) > 0 {
+		fields[key+"Chain"] = chain
+	}
+
+	if stack := errorStack(err); stack != "" {
+		fields[key+"Stack"] = stack
+	}
+
+	return fields
+}
+
+// ExpandErrors returns the fields with the error values replaced by
+// ErrorFields, keys set explicitly take precedence over the generated ones.
+func ExpandErrors(fields Fields) Fields {
+	hasErrors := false
+	for _, v := range fields {
+		if _, ok := v.(error); ok {
+			hasErrors = true
+			break
+		}
+	}
+
+	if !hasErrors {
+		return fields
+	}
+
+	expanded := make(Fields, len(fields))
+	for k, v := range fields {
+		err, ok := v.(error)
+		if !ok {
+			continue
+		}
+
+		for ek, ev := range ErrorFields(k, err) {
+			expanded[ek] = ev
+		}
+	}
+	for k, v := range fields {
+		if _, ok := v.(error); !ok {
+			expanded[k] = v
+		}
+	}
+
+	return expanded
+}
+
+// unwrapAll returns the errors wrapped by err depth-first.
+func unwrapAll(err error) []error {
+	var wrapped []error
+
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if next := e.Unwrap(); next != nil {
+			wrapped = append(wrapped, next)
+			wrapped = append(wrapped, unwrapAll(next)...)
+		}
+	case interface{ Unwrap() []error }:
+		for _, next := range e.Unwrap() {
+			if next == nil {
+				continue
+			}
+			wrapped = append(wrapped, next)
+			wrapped = append(wrapped, unwrapAll(next)...)
+		}
+	}
+
+	return wrapped
+}
+
+// errorStack returns the stack trace of the innermost error having
+// a StackTrace method. Its result is rendered with "%+v" unless it's
+// a list of program counters.
+func errorStack(err error) string {
+	chain := append([]error{err}, unwrapAll(err)...)
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		method := reflect.ValueOf(chain[i]).MethodByName("StackTrace")
+		if !method.IsValid() ||
+			method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+			continue
+		}
+
+		stack := method.Call(nil)[0].Interface()
+		if pcs, ok := stack.([]uintptr); ok {
+			return formatFrames(pcs)
+		}
+
+		return strings.TrimSpace(fmt.Sprintf("%+v", stack))
+	}
+
+	return ""
+}
+
+func formatFrames(pcs []uintptr) string {
+	b := &strings.Builder{}
+
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+
+		if !more {
+			break
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
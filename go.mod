@@ -2,9 +2,11 @@ module github.com/Chekunin/logman
 
 go 1.19
 
-require go.uber.org/zap v1.24.0
-
 require (
-	go.uber.org/atomic v1.7.0 // indirect
-	go.uber.org/multierr v1.6.0 // indirect
+	github.com/BurntSushi/toml v1.3.2
+	go.uber.org/multierr v1.6.0
+	go.uber.org/zap v1.24.0
+	gopkg.in/yaml.v3 v3.0.1
 )
+
+require go.uber.org/atomic v1.7.0 // indirect
@@ -0,0 +1,226 @@
+// Package http provides an http.Handler to inspect and change
+// the levels of a Logman at runtime.
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Chekunin/logman"
+)
+
+// maxBodySize limits the size of change requests.
+const maxBodySize = 1 << 16
+
+type HandlerConfig struct {
+	// Authorize is called before serving every request. A non-nil error
+	// rejects the request with 403 Forbidden.
+	Authorize func(r *http.Request) error
+	// TTL is the default time after which a changed level is reverted.
+	// Zero means changes are permanent unless a request specifies TTL.
+	TTL time.Duration
+}
+
+type channelState struct {
+	Driver string       `json:"driver"`
+	Level  logman.Level `json:"level"`
+}
+
+type state struct {
+	Level    logman.Level            `json:"level"`
+	Channels map[string]channelState `json:"channels"`
+}
+
+type changeRequest struct {
+	// Channel is the channel to change, the global level is changed
+	// if it's empty.
+	Channel string       `json:"channel"`
+	Level   logman.Level `json:"level"`
+	// TTL is a duration string ("10m") after which the previous
+	// level is restored.
+	TTL string `json:"ttl"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler serves the Logman level and the levels of its channels on GET
+// and changes one of them on PUT or POST with a JSON body like
+// {"channel": "stderr", "level": "debug", "ttl": "10m"}.
+type Handler struct {
+	lm  *logman.Logman
+	cfg HandlerConfig
+
+	mu      sync.Mutex
+	reverts map[string]*revert
+}
+
+type revert struct {
+	timer *time.Timer
+	level logman.Level
+}
+
+func NewHandler(lm *logman.Logman, cfg HandlerConfig) *Handler {
+	return &Handler{
+		lm:      lm,
+		cfg:     cfg,
+		reverts: map[string]*revert{},
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.cfg.Authorize != nil {
+		if err := h.cfg.Authorize(r); err != nil {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		if err := h.change(w, r); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		writeError(
+			w,
+			http.StatusMethodNotAllowed,
+			fmt.Errorf("Method %s is not allowed", r.Method),
+		)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.state())
+}
+
+func (h *Handler) state() state {
+	st := state{
+		Level:    h.lm.Level(),
+		Channels: map[string]channelState{},
+	}
+
+	cfg := h.lm.Config()
+
+	names := make([]string, 0, len(cfg.Channels))
+	for name := range cfg.Channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for name, ch := range h.lm.Channels(names...) {
+		st.Channels[name] = channelState{
+			Driver: cfg.Channels[name].DriverName(),
+			Level:  ch.Level(),
+		}
+	}
+
+	return st
+}
+
+func (h *Handler) change(w http.ResponseWriter, r *http.Request) error {
+	req := changeRequest{}
+	body := http.MaxBytesReader(w, r.Body, maxBodySize)
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		return fmt.Errorf("Failed to decode request: %w", err)
+	}
+
+	if req.Level == logman.NotSet {
+		return errors.New("No level defined")
+	}
+
+	ttl := h.cfg.TTL
+	if req.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.TTL); err != nil {
+			return fmt.Errorf("Invalid ttl: %w", err)
+		}
+	}
+
+	// the previous level is read and the revert is scheduled atomically
+	// with the change, so concurrent changes revert to the right level
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	prev, err := h.currentLevel(req.Channel)
+	if err != nil {
+		return err
+	}
+
+	if err := h.setLevel(req.Channel, req.Level); err != nil {
+		return err
+	}
+
+	h.scheduleRevert(req.Channel, prev, ttl)
+
+	return nil
+}
+
+// currentLevel returns the level to revert to, which is NotSet
+// for channels following the Logman level.
+func (h *Handler) currentLevel(channel string) (logman.Level, error) {
+	if channel == "" {
+		return h.lm.Level(), nil
+	}
+
+	return h.lm.ChannelLevel(channel)
+}
+
+func (h *Handler) setLevel(channel string, level logman.Level) error {
+	if channel != "" {
+		return h.lm.SetChannelLevel(channel, level)
+	}
+
+	return h.lm.SetLevel(level)
+}
+
+// scheduleRevert restores the previous level of the channel after ttl.
+// A pending revert of the same channel is replaced, keeping the level
+// from before the first of consecutive changes. h.mu must be held.
+func (h *Handler) scheduleRevert(
+	channel string,
+	prev logman.Level,
+	ttl time.Duration,
+) {
+	if r, exists := h.reverts[channel]; exists {
+		r.timer.Stop()
+		prev = r.level
+		delete(h.reverts, channel)
+	}
+
+	if ttl <= 0 {
+		return
+	}
+
+	r := &revert{level: prev}
+	r.timer = time.AfterFunc(ttl, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if h.reverts[channel] != r {
+			return
+		}
+		delete(h.reverts, channel)
+
+		_ = h.setLevel(channel, r.level)
+	})
+	h.reverts[channel] = r
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
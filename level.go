@@ -0,0 +1,124 @@
+package logman
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var levelNames = map[Level]string{
+	CriticalLevel: "critical",
+	ErrorLevel:    "error",
+	WarningLevel:  "warning",
+	InfoLevel:     "info",
+	DebugLevel:    "debug",
+}
+
+// ParseLevel parses a level name ("debug", "info", "warning", "error",
+// "critical") case-insensitively. Numeric levels are accepted as well.
+func ParseLevel(s string) (Level, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	for level, levelName := range levelNames {
+		if name == levelName {
+			return level, nil
+		}
+	}
+
+	switch name {
+	case "warn":
+		return WarningLevel, nil
+	case "crit":
+		return CriticalLevel, nil
+	}
+
+	if n, err := strconv.ParseUint(name, 10, 8); err == nil {
+		level := Level(n)
+		if level >= CriticalLevel && level <= DebugLevel {
+			return level, nil
+		}
+	}
+
+	return NotSet, fmt.Errorf("Level \"%s\": %w", s, InvalidLevelErr)
+}
+
+// ParseLevelValue parses a level from an option of
+// ChannelArbitraryConfig.Extra, which holds a name or a number.
+func ParseLevelValue(val interface{}) (Level, error) {
+	switch v := val.(type) {
+	case string:
+		return ParseLevel(v)
+	case Level, int, int64, float64, json.Number:
+		return ParseLevel(fmt.Sprint(v))
+	}
+
+	return NotSet, fmt.Errorf("Level \"%v\": %w", val, InvalidLevelErr)
+}
+
+// String returns the lowercase level name, e.g. "warning".
+func (l Level) String() string {
+	if name, exists := levelNames[l]; exists {
+		return name
+	}
+
+	if l == NotSet {
+		return "notset"
+	}
+
+	return fmt.Sprintf("Level(%d)", uint8(l))
+}
+
+// MarshalText marshals the level to its name, NotSet is marshalled
+// to an empty string.
+func (l Level) MarshalText() ([]byte, error) {
+	if l == NotSet {
+		return []byte{}, nil
+	}
+
+	if _, exists := levelNames[l]; !exists {
+		return nil, fmt.Errorf("Level \"%d\": %w", uint8(l), InvalidLevelErr)
+	}
+
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText unmarshals the level from its name or number,
+// an empty string is unmarshalled to NotSet.
+func (l *Level) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*l = NotSet
+		return nil
+	}
+
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+
+	*l = level
+
+	return nil
+}
+
+// UnmarshalJSON accepts both level names and numbers.
+func (l *Level) UnmarshalJSON(data []byte) error {
+	if s, err := strconv.Unquote(string(data)); err == nil {
+		return l.UnmarshalText([]byte(s))
+	}
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	return l.UnmarshalText(data)
+}
+
+// Set implements flag.Value.
+func (l *Level) Set(s string) error {
+	return l.UnmarshalText([]byte(s))
+}
+
+// Get implements flag.Getter.
+func (l *Level) Get() interface{} {
+	return *l
+}
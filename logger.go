@@ -1,5 +1,7 @@
 package logman
 
+import "context"
+
 type Logger interface {
 	// Debug logs a detailed debug information.
 	Debug(msg string, fields ...Fields)
@@ -17,8 +19,24 @@ type Logger interface {
 	Critical(msg string, fields ...Fields)
 	// Log logs with an arbitrary level.
 	Log(level Level, msg string, fields ...Fields)
+	// DebugContext logs a detailed debug information within ctx.
+	DebugContext(ctx context.Context, msg string, fields ...Fields)
+	// InfoContext logs general informational events within ctx.
+	InfoContext(ctx context.Context, msg string, fields ...Fields)
+	// WarningContext logs exceptional occurrences within ctx.
+	WarningContext(ctx context.Context, msg string, fields ...Fields)
+	// ErrorContext logs runtime errors within ctx.
+	ErrorContext(ctx context.Context, msg string, fields ...Fields)
+	// CriticalContext logs critical events within ctx.
+	CriticalContext(ctx context.Context, msg string, fields ...Fields)
+	// LogContext logs with an arbitrary level within ctx. Drivers may
+	// extract values from ctx, e.g. fields stored by ContextWithFields.
+	LogContext(ctx context.Context, level Level, msg string, fields ...Fields)
 	// level return current log level.
 	Level() Level
+	// With returns a child logger which attaches the given fields
+	// to every entry it logs.
+	With(fields Fields) Logger
 }
 
 type Fields map[string]interface{}
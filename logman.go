@@ -1,17 +1,28 @@
 package logman
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/multierr"
 )
 
 var (
 	DefaultChannelIsNotSetErr    = errors.New("Default channel is not set")
 	DriverIsNotSetErr            = errors.New("Driver is not set")
 	InvalidConfigValueErr        = errors.New("Invalid config value")
+	InvalidLevelErr              = errors.New("Invalid level")
 	MultipleInitErr              = errors.New("Already initialized")
 	NoChannelsConfiguredErr      = errors.New("No channels configured")
 	NoConfigForDefaultChannelErr = errors.New("No config for default channel")
+	DependencyCycleErr           = errors.New("Dependency cycle")
+	UnknownChannelErr            = errors.New("Unknown channel")
+	UnsupportedErr               = errors.New("Not supported")
 	UnknownDriverErr             = errors.New("Unknown driver")
 )
 
@@ -39,11 +50,66 @@ func Critical(msg string, fields ...Fields) {
 func Log(level Level, msg string, fields ...Fields) {
 	logger.Log(level, msg, fields...)
 }
+func DebugContext(ctx context.Context, msg string, fields ...Fields) {
+	FromContext(ctx).DebugContext(ctx, msg, fields...)
+}
+func InfoContext(ctx context.Context, msg string, fields ...Fields) {
+	FromContext(ctx).InfoContext(ctx, msg, fields...)
+}
+func WarningContext(ctx context.Context, msg string, fields ...Fields) {
+	FromContext(ctx).WarningContext(ctx, msg, fields...)
+}
+func ErrorContext(ctx context.Context, msg string, fields ...Fields) {
+	FromContext(ctx).ErrorContext(ctx, msg, fields...)
+}
+func CriticalContext(ctx context.Context, msg string, fields ...Fields) {
+	FromContext(ctx).CriticalContext(ctx, msg, fields...)
+}
+func LogContext(
+	ctx context.Context,
+	level Level,
+	msg string,
+	fields ...Fields,
+) {
+	FromContext(ctx).LogContext(ctx, level, msg, fields...)
+}
+func With(fields Fields) Logger {
+	return logger.With(fields)
+}
+func Channel(name string) Logger {
+	return logger.Channel(name)
+}
+func SetLevel(level Level) error {
+	return logger.SetLevel(level)
+}
 
 type Logman struct {
+	*core
+	fields     []Fields
+	callerSkip int
+	isInited   bool
+}
+
+// core is shared by a Logman and the loggers derived from it.
+type core struct {
+	state    atomic.Pointer[state]
+	level    *AtomicLevel
+	reloadMu sync.Mutex
+}
+
+// state is a config with the channels created for it. It's replaced
+// as a whole on reload.
+type state struct {
 	cfg      Config
 	channels map[string]Logger
-	isInited bool
+	// order is the order the channels are created in,
+	// dependencies go first.
+	order []string
+
+	// mu is held for reading while logging to the channels,
+	// so they're closed only after in-flight entries are written.
+	mu     sync.RWMutex
+	closed bool
 }
 
 func Init(cfg Config) error {
@@ -67,21 +133,39 @@ func InitOrPanic(cfg Config) {
 	}
 }
 func New(cfg Config) (*Logman, error) {
+	if err := cfg.setDefaults().validate(); err != nil {
+		return nil, fmt.Errorf("Invalid config <= %w", err)
+	}
+
 	lm := &Logman{
-		cfg:      cfg,
-		channels: map[string]Logger{},
+		core: &core{level: NewAtomicLevel(cfg.Level)},
 	}
 
-	if err := lm.cfg.setDefaults().validate(); err != nil {
-		return nil, fmt.Errorf("Invalid config <= %w", err)
+	if err := createState(lm, cfg); err != nil {
+		return nil, err
+	}
+
+	return lm, nil
+}
+
+// createState creates the channels of the config and stores them
+// as the state of lm. On error the channels created so far are closed.
+func createState(lm *Logman, cfg Config) error {
+	st := &state{
+		cfg:      cfg,
+		channels: map[string]Logger{},
 	}
+	lm.state.Store(st)
 
-	err := createChannels(lm, cfg.Channels)
+	err := createChannels(lm, st, cfg.Channels)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to create channels <= %w", err)
+		return fmt.Errorf(
+			"Failed to create channels <= %w",
+			multierr.Append(err, retireState(st)),
+		)
 	}
 
-	return lm, nil
+	return nil
 }
 func NewOrPanic(cfg Config) *Logman {
 	lm, err := New(cfg)
@@ -98,31 +182,163 @@ func newDefault() *Logman {
 		DefaultChannel: "std",
 		Level:          InfoLevel,
 		Channels: ChannelConfigs{
-			"std": stdLoggerConfig{},
+			"std": StdLoggerConfig{},
 		},
 	})
 }
 
-func createChannels(lm *Logman, chCfgs map[string]ChannelConfig) error {
-	for name, cfg := range chCfgs {
+func createChannels(
+	lm *Logman,
+	st *state,
+	chCfgs map[string]ChannelConfig,
+) error {
+	order, err := creationOrder(chCfgs)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range order {
+		cfg := chCfgs[name]
+
 		logger, err := drivers[cfg.DriverName()].CreateLogger(lm, cfg)
 		if err != nil {
 			return fmt.Errorf("Failed to create logger: %s <= %w", name, err)
 		}
 
-		lm.channels[name] = logger
+		st.channels[name] = logger
+		st.order = append(st.order, name)
+	}
+
+	for _, name := range order {
+		if i, ok := st.channels[name].(Initializer); ok {
+			if err := i.Init(lm); err != nil {
+				return fmt.Errorf(
+					"Failed to init logger: %s <= %w", name, err,
+				)
+			}
+		}
 	}
 
 	return nil
 }
 
+// creationOrder sorts the channels so that every channel goes after
+// the channels it depends on.
+func creationOrder(chCfgs map[string]ChannelConfig) ([]string, error) {
+	names := make([]string, 0, len(chCfgs))
+	for name := range chCfgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	deps := map[string][]string{}
+	for _, name := range names {
+		cfg := chCfgs[name]
+
+		d, ok := drivers[cfg.DriverName()].(DependentDriver)
+		if !ok {
+			continue
+		}
+
+		chDeps, err := d.Dependencies(cfg)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"Failed to get dependencies: %s <= %w", name, err,
+			)
+		}
+
+		for _, dep := range chDeps {
+			if _, exists := chCfgs[dep]; !exists {
+				return nil, fmt.Errorf(
+					"Channel \"%s\", dependency \"%s\": %w",
+					name, dep, UnknownChannelErr,
+				)
+			}
+		}
+		deps[name] = chDeps
+	}
+
+	// channels not visited yet have no mark
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	order := make([]string, 0, len(names))
+	marks := map[string]int{}
+	path := []string{}
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := []string{}
+			for i := len(path) - 1; i >= 0; i-- {
+				if path[i] == name {
+					cycle = append(cycle, path[i:]...)
+					break
+				}
+			}
+			cycle = append(cycle, name)
+
+			return fmt.Errorf(
+				"Channels %s: %w",
+				strings.Join(cycle, " -> "),
+				DependencyCycleErr,
+			)
+		}
+
+		marks[name] = visiting
+		path = append(path, name)
+		for _, dep := range deps[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		marks[name] = visited
+		order = append(order, name)
+
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// acquireState returns the current state locked for logging.
+// The caller must release it with st.mu.RUnlock().
+func (lm *Logman) acquireState() *state {
+	for {
+		st := lm.state.Load()
+
+		st.mu.RLock()
+		if !st.closed {
+			return st
+		}
+		st.mu.RUnlock()
+	}
+}
+
 func (lm *Logman) Config() Config {
-	return lm.cfg
+	cfg := lm.state.Load().cfg
+	cfg.Level = lm.Level()
+
+	return cfg
 }
 func (lm *Logman) Channels(name ...string) map[string]Logger {
+	st := lm.state.Load()
+
 	chans := map[string]Logger{}
 	for _, n := range name {
-		if ch, exists := lm.channels[n]; exists {
+		if ch, exists := st.channels[n]; exists {
 			chans[n] = ch
 		}
 	}
@@ -131,7 +347,61 @@ func (lm *Logman) Channels(name ...string) map[string]Logger {
 }
 
 func (lm *Logman) Level() Level {
-	return lm.cfg.Level
+	return lm.level.Level()
+}
+
+// SetLevel changes the Logman level. It is safe for concurrent use
+// and affects the loggers derived with With and Channel as well as
+// the channels which level isn't set.
+func (lm *Logman) SetLevel(level Level) error {
+	if level < CriticalLevel || level > DebugLevel {
+		return fmt.Errorf("Level \"%d\": %w", level, InvalidLevelErr)
+	}
+
+	lm.level.SetLevel(level)
+
+	return nil
+}
+
+// ChannelLevel returns the level set for the named channel, NotSet
+// if it follows the Logman level. The effective level is returned
+// for channels which driver doesn't implement OwnLevelGetter.
+func (lm *Logman) ChannelLevel(name string) (Level, error) {
+	ch, exists := lm.state.Load().channels[name]
+	if !exists {
+		return NotSet, fmt.Errorf("Channel \"%s\": %w", name, UnknownChannelErr)
+	}
+
+	if g, ok := ch.(OwnLevelGetter); ok {
+		return g.OwnLevel(), nil
+	}
+
+	return ch.Level(), nil
+}
+
+// SetChannelLevel changes the level of the named channel, NotSet makes
+// it follow the Logman level again. The channel driver has to support it
+// by implementing LevelSetter.
+func (lm *Logman) SetChannelLevel(name string, level Level) error {
+	if level > DebugLevel {
+		return fmt.Errorf("Level \"%d\": %w", level, InvalidLevelErr)
+	}
+
+	ch, exists := lm.state.Load().channels[name]
+	if !exists {
+		return fmt.Errorf("Channel \"%s\": %w", name, UnknownChannelErr)
+	}
+
+	setter, ok := ch.(LevelSetter)
+	if !ok {
+		return fmt.Errorf(
+			"Channel \"%s\", changing level: %w", name, UnsupportedErr,
+		)
+	}
+
+	setter.SetLevel(level)
+
+	return nil
 }
 func (lm *Logman) Debug(msg string, fields ...Fields) {
 	lm.Log(DebugLevel, msg, fields...)
@@ -149,23 +419,88 @@ func (lm *Logman) Critical(msg string, fields ...Fields) {
 	lm.Log(CriticalLevel, msg, fields...)
 }
 func (lm *Logman) Log(level Level, msg string, fields ...Fields) {
-	if lm.Level() < level {
+	lm.LogContext(context.Background(), level, msg, fields...)
+}
+func (lm *Logman) DebugContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	lm.LogContext(ctx, DebugLevel, msg, fields...)
+}
+func (lm *Logman) InfoContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	lm.LogContext(ctx, InfoLevel, msg, fields...)
+}
+func (lm *Logman) WarningContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	lm.LogContext(ctx, WarningLevel, msg, fields...)
+}
+func (lm *Logman) ErrorContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	lm.LogContext(ctx, ErrorLevel, msg, fields...)
+}
+func (lm *Logman) CriticalContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	lm.LogContext(ctx, CriticalLevel, msg, fields...)
+}
+func (lm *Logman) LogContext(
+	ctx context.Context,
+	level Level,
+	msg string,
+	fields ...Fields,
+) {
+	lm.logTo(ctx, "", level, msg, fields...)
+}
+
+// logTo logs to the named channel, the default one if chName is empty.
+func (lm *Logman) logTo(
+	ctx context.Context,
+	chName string,
+	level Level,
+	msg string,
+	fields ...Fields,
+) {
+	if len(lm.fields) > 0 {
+		fields = append(append([]Fields{}, lm.fields...), fields...)
+	}
+
+	st := lm.acquireState()
+	defer st.mu.RUnlock()
+
+	if chName == "" {
+		chName = st.cfg.DefaultChannel
+	}
+
+	ch, exists := st.channels[chName]
+	if !exists {
 		return
 	}
 
 	switch level {
-	case DebugLevel:
-		lm.channels[lm.cfg.DefaultChannel].Debug(msg, fields...)
-	case InfoLevel:
-		lm.channels[lm.cfg.DefaultChannel].Info(msg, fields...)
-	case WarningLevel:
-		lm.channels[lm.cfg.DefaultChannel].Warning(msg, fields...)
-	case ErrorLevel:
-		lm.channels[lm.cfg.DefaultChannel].Error(msg, fields...)
-	case CriticalLevel:
-		lm.channels[lm.cfg.DefaultChannel].Critical(msg, fields...)
+	case DebugLevel, InfoLevel, WarningLevel, ErrorLevel, CriticalLevel:
+		// the channel follows the Logman level unless it has its own one
+		if ch.Level() < level {
+			return
+		}
+
+		ctx = st.cfg.withRecord(ctx, level, lm.callerSkip)
+		ch.LogContext(ctx, level, msg, fields...)
 	default:
-		lm.channels[lm.cfg.DefaultChannel].Error(
+		ch.ErrorContext(
+			ctx,
 			"Unknown log level",
 			Fields{
 				"level":          level,
@@ -175,3 +510,27 @@ func (lm *Logman) Log(level Level, msg string, fields ...Fields) {
 		)
 	}
 }
+
+// With returns a copy of the Logman which attaches the given fields
+// to every entry logged through it. Channels are shared with the parent.
+func (lm *Logman) With(fields Fields) Logger {
+	return lm.with(fields)
+}
+func (lm *Logman) with(fields Fields) *Logman {
+	child := *lm
+	child.fields = append(append([]Fields{}, lm.fields...), fields)
+	child.isInited = false
+
+	return &child
+}
+
+// WithCallerSkip returns a logger skipping the given number of frames,
+// in addition to Config.CallerSkip, when capturing the caller. Packages
+// wrapping logman use it to report the callers of their functions.
+func (lm *Logman) WithCallerSkip(skip int) *Logman {
+	child := *lm
+	child.callerSkip += skip
+	child.isInited = false
+
+	return &child
+}
@@ -0,0 +1,29 @@
+package logman
+
+import "context"
+
+type nopLogger struct{}
+
+// NewNop returns a logger which discards all entries.
+func NewNop() Logger {
+	return nopLogger{}
+}
+
+func (nopLogger) Debug(string, ...Fields)                              {}
+func (nopLogger) Info(string, ...Fields)                               {}
+func (nopLogger) Warning(string, ...Fields)                            {}
+func (nopLogger) Error(string, ...Fields)                              {}
+func (nopLogger) Critical(string, ...Fields)                           {}
+func (nopLogger) Log(Level, string, ...Fields)                         {}
+func (nopLogger) DebugContext(context.Context, string, ...Fields)      {}
+func (nopLogger) InfoContext(context.Context, string, ...Fields)       {}
+func (nopLogger) WarningContext(context.Context, string, ...Fields)    {}
+func (nopLogger) ErrorContext(context.Context, string, ...Fields)      {}
+func (nopLogger) CriticalContext(context.Context, string, ...Fields)   {}
+func (nopLogger) LogContext(context.Context, Level, string, ...Fields) {}
+func (nopLogger) Level() Level {
+	return NotSet
+}
+func (l nopLogger) With(Fields) Logger {
+	return l
+}
@@ -0,0 +1,129 @@
+package logman
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+const modulePath = "github.com/Chekunin/logman"
+
+// Record holds the details of an entry captured by logman before it's
+// passed to the drivers, see Config.EnableCaller and Config.StackTraceLevel.
+type Record struct {
+	// Caller is the frame of the code calling logman, empty if
+	// it wasn't captured.
+	Caller runtime.Frame
+	Stack  string
+}
+
+// HasCaller reports whether the caller was captured.
+func (r Record) HasCaller() bool {
+	return r.Caller.File != ""
+}
+
+// ShortCaller returns the caller as "dir/file.go:line".
+func (r Record) ShortCaller() string {
+	if !r.HasCaller() {
+		return ""
+	}
+
+	file := r.Caller.File
+	if i := strings.LastIndexByte(file, '/'); i >= 0 {
+		if j := strings.LastIndexByte(file[:i], '/'); j >= 0 {
+			file = file[j+1:]
+		}
+	}
+
+	return fmt.Sprintf("%s:%d", file, r.Caller.Line)
+}
+
+// Fields returns the captured details as "caller" and "stacktrace"
+// fields for the drivers with no dedicated place for them.
+func (r Record) Fields() Fields {
+	fields := Fields{}
+	if r.HasCaller() {
+		fields["caller"] = r.ShortCaller()
+	}
+	if r.Stack != "" {
+		fields["stacktrace"] = r.Stack
+	}
+
+	return fields
+}
+
+// CaptureRecord captures the caller and optionally the stack trace,
+// skipping the frames of logman, its drivers and the standard log
+// packages, and then the given number of frames, e.g. of wrappers
+// around logman.
+func CaptureRecord(skip int, withStack bool) Record {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	rec := Record{}
+	stack := &strings.Builder{}
+	external := false
+	for {
+		frame, more := frames.Next()
+
+		if !external && !isInternalFrame(frame) {
+			external = true
+		}
+
+		if external && !rec.HasCaller() {
+			if skip > 0 {
+				skip--
+			} else {
+				rec.Caller = frame
+				if !withStack {
+					break
+				}
+			}
+		}
+
+		if rec.HasCaller() {
+			fmt.Fprintf(
+				stack, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line,
+			)
+		}
+
+		if !more {
+			break
+		}
+	}
+	rec.Stack = strings.TrimSuffix(stack.String(), "\n")
+
+	return rec
+}
+
+func isInternalFrame(frame runtime.Frame) bool {
+	for _, prefix := range []string{
+		modulePath + ".",
+		modulePath + "/",
+		"log.",
+		"log/slog.",
+	} {
+		if strings.HasPrefix(frame.Function, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ContextWithRecord returns a copy of ctx carrying the record.
+func ContextWithRecord(ctx context.Context, r Record) context.Context {
+	return context.WithValue(ctx, recordContextKey, r)
+}
+
+// RecordFromContext returns the record stored in ctx by ContextWithRecord.
+func RecordFromContext(ctx context.Context) (Record, bool) {
+	if ctx == nil {
+		return Record{}, false
+	}
+
+	r, ok := ctx.Value(recordContextKey).(Record)
+
+	return r, ok
+}
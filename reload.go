@@ -0,0 +1,125 @@
+package logman
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reload validates the config, creates the channels for it and replaces
+// the Logman config and channels with them. Entries being logged during
+// the swap are written to the previous channels, which are then synced
+// and closed. On error the Logman is left unchanged.
+//
+// Loggers derived with With and Channel switch to the new channels,
+// loggers returned by Channels keep the previous ones.
+func (lm *Logman) Reload(cfg Config) error {
+	lm.reloadMu.Lock()
+	defer lm.reloadMu.Unlock()
+
+	if err := cfg.setDefaults().validate(); err != nil {
+		return fmt.Errorf("Logman reload failed <= Invalid config <= %w", err)
+	}
+
+	// the channels are created with a Logman sharing the level of lm
+	// so the ones following its level keep following it after the swap
+	fresh := &Logman{core: &core{level: lm.level}}
+	if err := createState(fresh, cfg); err != nil {
+		return fmt.Errorf("Logman reload failed <= %w", err)
+	}
+
+	next := fresh.state.Load()
+	prev := lm.state.Swap(next)
+	lm.level.SetLevel(next.cfg.Level)
+
+	if err := retireState(prev); err != nil {
+		lm.Error(
+			"Failed to close channels of the previous config",
+			Fields{"error": err},
+		)
+	}
+
+	return nil
+}
+
+func Reload(cfg Config) error {
+	return logger.Reload(cfg)
+}
+
+type WatchConfig struct {
+	// Interval between checks of the file, 5 seconds by default.
+	Interval time.Duration
+	// Prepare is applied to every loaded config before reload,
+	// e.g. to apply env overrides with Config.ApplyEnv.
+	Prepare func(cfg Config) (Config, error)
+	// OnReload is called after every reload attempt. Failures are logged
+	// with the Logman if it's not set.
+	OnReload func(err error)
+}
+
+// WatchConfigFile polls the config file and reloads the Logman with it
+// whenever its content changes. The returned function stops watching.
+func (lm *Logman) WatchConfigFile(path string, cfg WatchConfig) (stop func()) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Second
+	}
+
+	if cfg.OnReload == nil {
+		cfg.OnReload = func(err error) {
+			if err != nil {
+				lm.Error(
+					"Failed to reload config",
+					Fields{"path": path, "error": err},
+				)
+			}
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+
+		content, _ := os.ReadFile(path)
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			next, err := os.ReadFile(path)
+			if err != nil || bytes.Equal(next, content) {
+				continue
+			}
+			content = next
+
+			cfg.OnReload(lm.reloadFile(path, cfg.Prepare))
+		}
+	}()
+
+	once := sync.Once{}
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+func (lm *Logman) reloadFile(
+	path string,
+	prepare func(cfg Config) (Config, error),
+) error {
+	cfg, err := LoadConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	if prepare != nil {
+		if cfg, err = prepare(cfg); err != nil {
+			return fmt.Errorf("Failed to prepare config: %w", err)
+		}
+	}
+
+	return lm.Reload(cfg)
+}
@@ -0,0 +1,76 @@
+//go:build go1.21
+
+package slogbridge
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/Chekunin/logman"
+)
+
+type LoggerConfig struct {
+	Level   logman.Level
+	Handler slog.Handler
+}
+
+func (c LoggerConfig) DriverName() string {
+	return DriverName
+}
+func (c *LoggerConfig) setDefaults(lm *logman.Logman) *LoggerConfig {
+	return c
+}
+func (c LoggerConfig) validate(_ *logman.Logman) error {
+	if c.Level > logman.DebugLevel {
+		return fmt.Errorf("Invalid log level: %d", c.Level)
+	}
+
+	if c.Handler == nil {
+		return errors.New("No \"handler\" defined")
+	}
+
+	return nil
+}
+
+func parseConfig(c logman.ChannelConfig) (LoggerConfig, error) {
+	if cfg, ok := c.(LoggerConfig); ok {
+		return cfg, nil
+	}
+
+	cfg := LoggerConfig{}
+
+	rawCfg, ok := c.(logman.ChannelArbitraryConfig)
+	if !ok {
+		return cfg, errors.New("Invalid config structure")
+	}
+
+	cfg.Level = rawCfg.Level
+
+	for option, val := range rawCfg.Extra {
+		if option != "handler" {
+			return cfg, fmt.Errorf("Unknown option passsed: %s", option)
+		}
+
+		// the handler is either set in code or named for config files
+		switch handler := val.(type) {
+		case slog.Handler:
+			cfg.Handler = handler
+		case string:
+			opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+			switch handler {
+			case "text":
+				cfg.Handler = slog.NewTextHandler(os.Stderr, opts)
+			case "json":
+				cfg.Handler = slog.NewJSONHandler(os.Stderr, opts)
+			default:
+				return cfg, fmt.Errorf("Unknown handler: %s", handler)
+			}
+		default:
+			return cfg, errors.New("Failed to parse \"handler\" option")
+		}
+	}
+
+	return cfg, nil
+}
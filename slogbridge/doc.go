@@ -0,0 +1,4 @@
+// Package slogbridge connects logman and log/slog in both directions:
+// Handler passes slog records to a logman.Logger and the "slog" driver
+// writes logman entries to a slog.Handler. It requires Go 1.21.
+package slogbridge
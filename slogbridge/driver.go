@@ -0,0 +1,179 @@
+//go:build go1.21
+
+package slogbridge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/Chekunin/logman"
+)
+
+const DriverName = "slog"
+
+type driver struct{}
+
+func (d driver) CreateLogger(
+	lm *logman.Logman,
+	c logman.ChannelConfig,
+) (logman.Logger, error) {
+	cfg, err := parseConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse config: %w", err)
+	}
+
+	return newLogger(cfg, lm)
+}
+
+type logger struct {
+	cfg     LoggerConfig
+	level   *logman.AtomicLevel
+	handler slog.Handler
+}
+
+func newLogger(cfg LoggerConfig, lm *logman.Logman) (*logger, error) {
+	if err := cfg.setDefaults(lm).validate(lm); err != nil {
+		return nil, fmt.Errorf("Invalid config: %w", err)
+	}
+
+	return &logger{
+		cfg:     cfg,
+		level:   lm.NewChannelLevel(cfg.Level),
+		handler: cfg.Handler,
+	}, nil
+}
+
+func (l *logger) Debug(msg string, fields ...logman.Fields) {
+	l.Log(logman.DebugLevel, msg, fields...)
+}
+func (l *logger) Info(msg string, fields ...logman.Fields) {
+	l.Log(logman.InfoLevel, msg, fields...)
+}
+func (l *logger) Warning(msg string, fields ...logman.Fields) {
+	l.Log(logman.WarningLevel, msg, fields...)
+}
+func (l *logger) Error(msg string, fields ...logman.Fields) {
+	l.Log(logman.ErrorLevel, msg, fields...)
+}
+func (l *logger) Critical(msg string, fields ...logman.Fields) {
+	l.Log(logman.CriticalLevel, msg, fields...)
+}
+func (l *logger) Log(level logman.Level, msg string, fields ...logman.Fields) {
+	l.LogContext(context.Background(), level, msg, fields...)
+}
+func (l *logger) DebugContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.DebugLevel, msg, fields...)
+}
+func (l *logger) InfoContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.InfoLevel, msg, fields...)
+}
+func (l *logger) WarningContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.WarningLevel, msg, fields...)
+}
+func (l *logger) ErrorContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.ErrorLevel, msg, fields...)
+}
+func (l *logger) CriticalContext(
+	ctx context.Context,
+	msg string,
+	fields ...logman.Fields,
+) {
+	l.LogContext(ctx, logman.CriticalLevel, msg, fields...)
+}
+func (l *logger) LogContext(
+	ctx context.Context,
+	level logman.Level,
+	msg string,
+	fields ...logman.Fields,
+) {
+	if l.Level() < level {
+		return
+	}
+
+	slogLevel := ToSlogLevel(level)
+	if !l.handler.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	// the caller is passed as the record PC for handlers adding source
+	rec, _ := logman.RecordFromContext(ctx)
+
+	r := slog.NewRecord(time.Now(), slogLevel, msg, rec.Caller.PC)
+	if rec.Stack != "" {
+		r.AddAttrs(slog.String("stacktrace", rec.Stack))
+	}
+	for _, fieldSet := range logman.FieldsFromContext(ctx) {
+		r.AddAttrs(toAttrs(logman.ExpandErrors(fieldSet))...)
+	}
+	for _, fieldSet := range fields {
+		r.AddAttrs(toAttrs(logman.ExpandErrors(fieldSet))...)
+	}
+
+	_ = l.handler.Handle(ctx, r)
+}
+func (l *logger) Level() logman.Level {
+	return l.level.Level()
+}
+func (l *logger) SetLevel(level logman.Level) {
+	l.level.SetLevel(level)
+}
+func (l *logger) OwnLevel() logman.Level {
+	return l.level.OwnLevel()
+}
+func (l *logger) With(fields logman.Fields) logman.Logger {
+	child := *l
+	child.handler = l.handler.WithAttrs(toAttrs(logman.ExpandErrors(fields)))
+
+	return &child
+}
+
+// toAttrs converts the fields to attributes sorted by key,
+// nested Fields become groups.
+func toAttrs(fields map[string]interface{}) []slog.Attr {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		switch v := fields[k].(type) {
+		case logman.Fields:
+			attrs = append(attrs, groupAttr(k, v))
+		case map[string]interface{}:
+			attrs = append(attrs, groupAttr(k, v))
+		default:
+			attrs = append(attrs, slog.Any(k, v))
+		}
+	}
+
+	return attrs
+}
+
+func groupAttr(key string, fields map[string]interface{}) slog.Attr {
+	return slog.Attr{Key: key, Value: slog.GroupValue(toAttrs(fields)...)}
+}
+
+func init() {
+	logman.RegisterDriver(DriverName, driver{})
+}
@@ -0,0 +1,186 @@
+//go:build go1.21
+
+package slogbridge
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"github.com/Chekunin/logman"
+)
+
+type HandlerOptions struct {
+	// Level limits the records handled in addition to the level
+	// of the logger.
+	Level slog.Leveler
+	// AddSource passes the source of the records to the logger
+	// as the caller of logman.Record.
+	AddSource bool
+}
+
+// Handler is a slog.Handler writing records to a logman.Logger.
+// Groups are passed as nested Fields.
+type Handler struct {
+	logger logman.Logger
+	opts   HandlerOptions
+	groups []string
+	// grouped holds the attributes added after a group was opened,
+	// the ones added before are bound to the logger.
+	grouped logman.Fields
+}
+
+func NewHandler(logger logman.Logger, opts *HandlerOptions) *Handler {
+	h := &Handler{logger: logger}
+	if opts != nil {
+		h.opts = *opts
+	}
+
+	return h
+}
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	if h.opts.Level != nil && level < h.opts.Level.Level() {
+		return false
+	}
+
+	return FromSlogLevel(level) <= h.logger.Level()
+}
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	ctx = h.withRecord(ctx, r)
+
+	fields := cloneFields(h.grouped)
+	if fields == nil {
+		fields = logman.Fields{}
+	}
+
+	target := groupFields(fields, h.groups)
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(target, a)
+		return true
+	})
+
+	if len(fields) == 0 {
+		h.logger.LogContext(ctx, FromSlogLevel(r.Level), r.Message)
+		return nil
+	}
+
+	h.logger.LogContext(ctx, FromSlogLevel(r.Level), r.Message, fields)
+
+	return nil
+}
+func (h *Handler) withRecord(
+	ctx context.Context,
+	r slog.Record,
+) context.Context {
+	if !h.opts.AddSource || r.PC == 0 {
+		return ctx
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	} else if _, exists := logman.RecordFromContext(ctx); exists {
+		return ctx
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+
+	return logman.ContextWithRecord(ctx, logman.Record{Caller: frame})
+}
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	child := *h
+
+	if len(h.groups) == 0 {
+		fields := logman.Fields{}
+		for _, a := range attrs {
+			addAttr(fields, a)
+		}
+		child.logger = h.logger.With(fields)
+
+		return &child
+	}
+
+	child.grouped = cloneFields(h.grouped)
+	if child.grouped == nil {
+		child.grouped = logman.Fields{}
+	}
+
+	target := groupFields(child.grouped, h.groups)
+	for _, a := range attrs {
+		addAttr(target, a)
+	}
+
+	return &child
+}
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	child := *h
+	child.groups = append(append([]string{}, h.groups...), name)
+
+	return &child
+}
+
+// addAttr adds the attribute to the fields, groups become nested Fields.
+func addAttr(fields logman.Fields, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		fields[a.Key] = a.Value.Any()
+		return
+	}
+
+	groupAttrs := a.Value.Group()
+	if len(groupAttrs) == 0 {
+		return
+	}
+
+	target := fields
+	if a.Key != "" {
+		target = groupFields(fields, []string{a.Key})
+	}
+
+	for _, ga := range groupAttrs {
+		addAttr(target, ga)
+	}
+}
+
+// groupFields returns the nested Fields at the path creating them
+// if needed.
+func groupFields(fields logman.Fields, path []string) logman.Fields {
+	for _, name := range path {
+		nested, ok := fields[name].(logman.Fields)
+		if !ok {
+			nested = logman.Fields{}
+			fields[name] = nested
+		}
+		fields = nested
+	}
+
+	return fields
+}
+
+func cloneFields(fields logman.Fields) logman.Fields {
+	if fields == nil {
+		return nil
+	}
+
+	clone := make(logman.Fields, len(fields))
+	for k, v := range fields {
+		if nested, ok := v.(logman.Fields); ok {
+			v = cloneFields(nested)
+		}
+		clone[k] = v
+	}
+
+	return clone
+}
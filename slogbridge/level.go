@@ -0,0 +1,44 @@
+//go:build go1.21
+
+package slogbridge
+
+import (
+	"log/slog"
+
+	"github.com/Chekunin/logman"
+)
+
+// LevelCritical is the slog level logman.CriticalLevel is mapped to.
+const LevelCritical = slog.LevelError + 4
+
+// FromSlogLevel maps a slog level to the closest logman level.
+func FromSlogLevel(level slog.Level) logman.Level {
+	switch {
+	case level >= LevelCritical:
+		return logman.CriticalLevel
+	case level >= slog.LevelError:
+		return logman.ErrorLevel
+	case level >= slog.LevelWarn:
+		return logman.WarningLevel
+	case level >= slog.LevelInfo:
+		return logman.InfoLevel
+	default:
+		return logman.DebugLevel
+	}
+}
+
+// ToSlogLevel maps a logman level to a slog level.
+func ToSlogLevel(level logman.Level) slog.Level {
+	switch level {
+	case logman.CriticalLevel:
+		return LevelCritical
+	case logman.ErrorLevel:
+		return slog.LevelError
+	case logman.WarningLevel:
+		return slog.LevelWarn
+	case logman.InfoLevel:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
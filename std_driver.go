@@ -1,47 +1,142 @@
 package logman
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
 	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 )
 
 const DriverName = "std"
 
 type stdDriver struct{}
 
-func (d stdDriver) CreateLogger(_ *Logman, c ChannelConfig) (Logger, error) {
+func (d stdDriver) CreateLogger(lm *Logman, c ChannelConfig) (Logger, error) {
 	cfg, err := parseConfig(c)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to parse config: %w", err)
 	}
-	return newLogger(cfg), nil
-}
 
-var levelLabels = map[Level]string{
-	CriticalLevel: "CRT",
-	ErrorLevel:    "ERR",
-	WarningLevel:  "WRN",
-	InfoLevel:     "INF",
-	DebugLevel:    "DBG",
+	return newLogger(cfg, lm)
 }
 
-type stdLoggerConfig struct{}
+type StdLoggerConfig struct {
+	// Level follows the Logman level if it's not set.
+	Level Level
+	// Output is written to if it's set, otherwise Path is used.
+	Output io.Writer
+	// Path is "stderr" (default), "stdout" or a path of a file
+	// to append to.
+	Path string
+	// Encoding is "text" (default), "logfmt" or "json".
+	Encoding string
+	// TimeFormat defaults to "2006/01/02 15:04:05.000000" for text
+	// and to time.RFC3339Nano for the other encodings.
+	TimeFormat string
+	// LocalTime formats the time in the local time zone instead of UTC.
+	LocalTime   bool
+	LevelLabels map[Level]string
+}
 
-func (lc stdLoggerConfig) DriverName() string {
+func (c StdLoggerConfig) DriverName() string {
 	return DriverName
 }
+func (c *StdLoggerConfig) setDefaults() *StdLoggerConfig {
+	if c.Path == "" {
+		c.Path = "stderr"
+	}
+
+	if c.Encoding == "" {
+		c.Encoding = "text"
+	}
+
+	if c.TimeFormat == "" {
+		if c.Encoding == "text" {
+			c.TimeFormat = "2006/01/02 15:04:05.000000"
+		} else {
+			c.TimeFormat = time.RFC3339Nano
+		}
+	}
+
+	labels := map[Level]string{}
+	for level := range levelNames {
+		if c.Encoding == "text" {
+			labels[level] = strings.ToUpper(level.String())
+		} else {
+			labels[level] = level.String()
+		}
+	}
+	for level, label := range c.LevelLabels {
+		labels[level] = label
+	}
+	c.LevelLabels = labels
+
+	return c
+}
+func (c StdLoggerConfig) validate() error {
+	if c.Level > DebugLevel {
+		return fmt.Errorf("Invalid log level: %d", c.Level)
+	}
+
+	if c.Encoding != "text" && c.Encoding != "logfmt" && c.Encoding != "json" {
+		return fmt.Errorf("Invalid encoding: %s", c.Encoding)
+	}
+
+	return nil
+}
 
 type stdLogger struct {
-	*log.Logger
-	level Level
+	cfg    StdLoggerConfig
+	level  *AtomicLevel
+	out    *stdOutput
+	fields []Fields
 }
 
-func newLogger(_ stdLoggerConfig) *stdLogger {
-	return &stdLogger{
-		log.New(os.Stderr, "", log.Ldate|log.Ltime|log.Lmicroseconds|log.LUTC),
-		DebugLevel,
+// stdOutput serializes writes of the loggers sharing it.
+type stdOutput struct {
+	mu sync.Mutex
+	w  io.Writer
+	f  *os.File
+}
+
+func newLogger(cfg StdLoggerConfig, lm *Logman) (*stdLogger, error) {
+	if err := cfg.setDefaults().validate(); err != nil {
+		return nil, fmt.Errorf("Invalid config: %w", err)
+	}
+
+	out := &stdOutput{w: cfg.Output}
+	if out.w == nil {
+		switch cfg.Path {
+		case "stderr":
+			out.w = os.Stderr
+		case "stdout":
+			out.w = os.Stdout
+		default:
+			f, err := os.OpenFile(
+				cfg.Path,
+				os.O_WRONLY|os.O_APPEND|os.O_CREATE,
+				0o644,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to open output: %w", err)
+			}
+			out.w, out.f = f, f
+		}
 	}
+
+	return &stdLogger{
+		cfg:   cfg,
+		level: lm.NewChannelLevel(cfg.Level),
+		out:   out,
+	}, nil
 }
 
 func (l *stdLogger) Debug(msg string, fields ...Fields) {
@@ -64,20 +159,275 @@ func (l *stdLogger) Critical(msg string, fields ...Fields) {
 
 }
 func (l *stdLogger) Log(level Level, msg string, fields ...Fields) {
-	if l.level < level {
+	l.LogContext(context.Background(), level, msg, fields...)
+}
+func (l *stdLogger) DebugContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	l.LogContext(ctx, DebugLevel, msg, fields...)
+}
+func (l *stdLogger) InfoContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	l.LogContext(ctx, InfoLevel, msg, fields...)
+}
+func (l *stdLogger) WarningContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	l.LogContext(ctx, WarningLevel, msg, fields...)
+}
+func (l *stdLogger) ErrorContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	l.LogContext(ctx, ErrorLevel, msg, fields...)
+}
+func (l *stdLogger) CriticalContext(
+	ctx context.Context,
+	msg string,
+	fields ...Fields,
+) {
+	l.LogContext(ctx, CriticalLevel, msg, fields...)
+}
+func (l *stdLogger) LogContext(
+	ctx context.Context,
+	level Level,
+	msg string,
+	fields ...Fields,
+) {
+	if l.Level() < level {
 		return
 	}
 
-	l.Printf("[%s] %s %+v\n", levelLabels[level], msg, fields)
+	ctxFields := FieldsFromContext(ctx)
+	all := make([]Fields, 0, len(l.fields)+len(ctxFields)+len(fields)+1)
+	if rec, exists := RecordFromContext(ctx); exists {
+		all = append(all, rec.Fields())
+	}
+	all = append(all, l.fields...)
+	all = append(all, ctxFields...)
+	all = append(all, fields...)
+
+	line := l.encode(time.Now(), level, msg, all)
+
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+
+	_, _ = l.out.w.Write(line)
 }
 func (l *stdLogger) Level() Level {
-	return l.level
+	return l.level.Level()
+}
+func (l *stdLogger) SetLevel(level Level) {
+	l.level.SetLevel(level)
+}
+func (l *stdLogger) OwnLevel() Level {
+	return l.level.OwnLevel()
+}
+func (l *stdLogger) With(fields Fields) Logger {
+	child := *l
+	child.fields = append(append([]Fields{}, l.fields...), fields)
+
+	return &child
+}
+func (l *stdLogger) Sync() error {
+	if l.out.f == nil {
+		return nil
+	}
+
+	return l.out.f.Sync()
+}
+func (l *stdLogger) Close() error {
+	if l.out.f == nil {
+		return nil
+	}
+
+	return l.out.f.Close()
+}
+
+// encode encodes an entry to a line, later fields override earlier ones.
+func (l *stdLogger) encode(
+	ts time.Time,
+	level Level,
+	msg string,
+	fields []Fields,
+) []byte {
+	merged := map[string]interface{}{}
+	for _, fieldSet := range fields {
+		for k, v := range ExpandErrors(fieldSet) {
+			merged[k] = v
+		}
+	}
+
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if !l.cfg.LocalTime {
+		ts = ts.UTC()
+	}
+
+	label, exists := l.cfg.LevelLabels[level]
+	if !exists {
+		label = level.String()
+	}
+
+	buf := &bytes.Buffer{}
+
+	switch l.cfg.Encoding {
+	case "text":
+		buf.WriteString(ts.Format(l.cfg.TimeFormat))
+		buf.WriteString(" [")
+		buf.WriteString(label)
+		buf.WriteString("] ")
+		buf.WriteString(msg)
+		for _, k := range keys {
+			buf.WriteByte(' ')
+			writeLogfmtPair(buf, k, merged[k])
+		}
+	case "logfmt":
+		writeLogfmtPair(buf, "time", ts.Format(l.cfg.TimeFormat))
+		buf.WriteByte(' ')
+		writeLogfmtPair(buf, "level", label)
+		buf.WriteByte(' ')
+		writeLogfmtPair(buf, "msg", msg)
+		for _, k := range keys {
+			buf.WriteByte(' ')
+			writeLogfmtPair(buf, fieldKey(k), merged[k])
+		}
+	case "json":
+		buf.WriteString(`{"time":`)
+		buf.Write(marshalJSON(ts.Format(l.cfg.TimeFormat)))
+		buf.WriteString(`,"level":`)
+		buf.Write(marshalJSON(label))
+		buf.WriteString(`,"msg":`)
+		buf.Write(marshalJSON(msg))
+		for _, k := range keys {
+			buf.WriteByte(',')
+			buf.Write(marshalJSON(fieldKey(k)))
+			buf.WriteByte(':')
+			buf.Write(marshalJSON(merged[k]))
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte('\n')
+
+	return buf.Bytes()
 }
 
-func parseConfig(c ChannelConfig) (stdLoggerConfig, error) {
-	if cfg, ok := c.(stdLoggerConfig); ok {
+// fieldKey prefixes the keys of fields colliding with the keys
+// of the entry itself with "fields.".
+func fieldKey(key string) string {
+	switch key {
+	case "time", "level", "msg":
+		return "fields." + key
+	}
+
+	return key
+}
+
+func writeLogfmtPair(buf *bytes.Buffer, key string, val interface{}) {
+	buf.WriteString(logfmtValue(key))
+	buf.WriteByte('=')
+
+	if val == nil {
+		return
+	}
+
+	buf.WriteString(logfmtValue(fmt.Sprintf("%+v", val)))
+}
+
+// logfmtValue quotes the value if it's empty or contains spaces,
+// quotes, '=' or control characters.
+func logfmtValue(s string) string {
+	needsQuotes := s == "" || strings.IndexFunc(s, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == '\\' ||
+			unicode.IsSpace(r) || !unicode.IsPrint(r)
+	}) >= 0
+	if !needsQuotes {
+		return s
+	}
+
+	return fmt.Sprintf("%q", s)
+}
+
+func marshalJSON(v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("%+v", v))
+	}
+
+	return b
+}
+
+func parseConfig(c ChannelConfig) (StdLoggerConfig, error) {
+	if cfg, ok := c.(StdLoggerConfig); ok {
 		return cfg, nil
 	}
 
-	return stdLoggerConfig{}, nil
+	cfg := StdLoggerConfig{}
+
+	rawCfg, ok := c.(ChannelArbitraryConfig)
+	if !ok {
+		return cfg, errors.New("Invalid config structure")
+	}
+
+	cfg.Level = rawCfg.Level
+
+	for option, val := range rawCfg.Extra {
+		var ok bool
+
+		switch option {
+		case "path":
+			cfg.Path, ok = val.(string)
+		case "encoding":
+			cfg.Encoding, ok = val.(string)
+		case "timeFormat":
+			cfg.TimeFormat, ok = val.(string)
+		case "localTime":
+			cfg.LocalTime, ok = val.(bool)
+		case "levelLabels":
+			var rawLabels map[interface{}]interface{}
+			rawLabels, ok = val.(map[interface{}]interface{})
+			if !ok {
+				break
+			}
+
+			cfg.LevelLabels = map[Level]string{}
+			for rawLevel, rawLabel := range rawLabels {
+				name, isString := rawLevel.(string)
+				label, isLabel := rawLabel.(string)
+				if !isString || !isLabel {
+					return cfg, errors.New(
+						"Failed to parse \"levelLabels\" option",
+					)
+				}
+
+				level, err := ParseLevel(name)
+				if err != nil {
+					return cfg, fmt.Errorf(
+						"Failed to parse \"levelLabels\" option: %w", err,
+					)
+				}
+				cfg.LevelLabels[level] = label
+			}
+		default:
+			return cfg, fmt.Errorf("Unknown option passsed: %s", option)
+		}
+
+		if !ok {
+			return cfg, fmt.Errorf("Failed to parse \"%s\" option", option)
+		}
+	}
+
+	return cfg, nil
 }
@@ -0,0 +1,88 @@
+package logman
+
+import (
+	"bytes"
+	"log"
+	"sync"
+)
+
+// NewStdLog returns a standard library logger writing to the logger
+// with the level.
+func NewStdLog(logger Logger, level Level) *log.Logger {
+	return log.New(Writer(logger, level), "", 0)
+}
+
+// RedirectStdLog makes the standard library log package write
+// to the logger with the level until restore is called.
+func RedirectStdLog(logger Logger, level Level) (restore func()) {
+	prevOutput, prevFlags, prevPrefix := log.Writer(), log.Flags(), log.Prefix()
+
+	log.SetOutput(Writer(logger, level))
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	return func() {
+		log.SetOutput(prevOutput)
+		log.SetFlags(prevFlags)
+		log.SetPrefix(prevPrefix)
+	}
+}
+
+// Writer returns a writer logging each written line as a message.
+// Incomplete lines are buffered until the rest is written or Sync
+// is called.
+func Writer(logger Logger, level Level) *LineWriter {
+	return &LineWriter{logger: logger, level: level}
+}
+
+// LineWriter is an io.Writer logging each written line as a message,
+// see Writer.
+type LineWriter struct {
+	logger Logger
+	level  Level
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		w.log(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+
+	return len(p), nil
+}
+
+// Sync logs the buffered incomplete line.
+func (w *LineWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.log(w.buf)
+	w.buf = nil
+
+	return nil
+}
+
+func (w *LineWriter) log(line []byte) {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	if len(line) == 0 {
+		return
+	}
+
+	w.logger.Log(w.level, string(line))
+}
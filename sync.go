@@ -0,0 +1,102 @@
+package logman
+
+import (
+	"fmt"
+
+	"go.uber.org/multierr"
+)
+
+// Syncer is implemented by loggers which buffer entries.
+type Syncer interface {
+	// Sync flushes buffered entries.
+	Sync() error
+}
+
+// Closer is implemented by loggers which hold resources,
+// e.g. opened files or connections.
+type Closer interface {
+	// Close flushes buffered entries and releases the resources.
+	// The logger must not be used after that.
+	Close() error
+}
+
+// Sync flushes every channel implementing Syncer.
+func (lm *Logman) Sync() error {
+	return lm.state.Load().sync()
+}
+
+// Close waits for in-flight entries, then syncs and closes every channel.
+// Entries logged after that are discarded.
+func (lm *Logman) Close() error {
+	lm.reloadMu.Lock()
+	defer lm.reloadMu.Unlock()
+
+	prev := lm.state.Swap(&state{
+		cfg:      lm.state.Load().cfg,
+		channels: map[string]Logger{},
+	})
+
+	return retireState(prev)
+}
+
+// Sync flushes the channels of the current logger,
+// to be deferred in main.
+func Sync() error {
+	return logger.Sync()
+}
+
+// Close closes the channels of the current logger.
+func Close() error {
+	return logger.Close()
+}
+
+// retireState waits for in-flight entries of the replaced state
+// and closes its channels.
+func retireState(st *state) error {
+	st.mu.Lock()
+	st.closed = true
+	st.mu.Unlock()
+
+	return st.close()
+}
+
+// sync syncs the channels implementing Syncer, dependent channels go
+// first as they may flush entries to the channels they depend on.
+func (st *state) sync() error {
+	var err error
+	for i := len(st.order) - 1; i >= 0; i-- {
+		name := st.order[i]
+
+		if s, ok := st.channels[name].(Syncer); ok {
+			if syncErr := s.Sync(); syncErr != nil {
+				err = multierr.Append(
+					err,
+					fmt.Errorf("Failed to sync channel %s: %w", name, syncErr),
+				)
+			}
+		}
+	}
+
+	return err
+}
+
+// close syncs the channels, then closes the ones implementing Closer,
+// dependent channels go first.
+func (st *state) close() error {
+	err := st.sync()
+
+	for i := len(st.order) - 1; i >= 0; i-- {
+		name := st.order[i]
+
+		if c, ok := st.channels[name].(Closer); ok {
+			if closeErr := c.Close(); closeErr != nil {
+				err = multierr.Append(
+					err,
+					fmt.Errorf("Failed to close channel %s: %w", name, closeErr),
+				)
+			}
+		}
+	}
+
+	return err
+}